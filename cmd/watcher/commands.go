@@ -0,0 +1,463 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+	"github.com/overwatch144/golang-watcherclient/watcherclient/formatter"
+)
+
+// command is a CLI subcommand. setup registers its flags and returns the
+// function executing it with the positional arguments.
+type command struct {
+	resource string
+	name     string
+	usage    string
+	args     int // Minimum number of positional arguments
+	setup    func(fs *flag.FlagSet, ctx *context) func(args []string) error
+}
+
+// Columns of the data model elements
+var (
+	computeModelColumns = []string{"server_uuid", "server_name", "server_state", "node_uuid", "node_hostname"}
+	storageModelColumns = []string{"volume_uuid", "volume_name", "volume_status", "pool_name", "node_host"}
+)
+
+var commands = []command{
+	{resource: "audit", name: "list", usage: "[--limit N] [--marker UUID] [--sort-key KEY] [--sort-dir asc|desc]", setup: auditList},
+	{resource: "audit", name: "show", usage: "<audit>", args: 1, setup: auditShow},
+	{resource: "audit", name: "create", usage: "--goal GOAL [--strategy STRATEGY] [--audit-template TEMPLATE] [-p name=value]...", setup: auditCreate},
+	{resource: "audit", name: "update", usage: "<audit> <attribute=value>...", args: 2, setup: auditUpdate},
+	{resource: "audit", name: "delete", usage: "<audit>...", args: 1, setup: auditDelete},
+
+	{resource: "audittemplate", name: "list", usage: "[list flags]", setup: auditTemplateList},
+	{resource: "audittemplate", name: "show", usage: "<audit template>", args: 1, setup: auditTemplateShow},
+	{resource: "audittemplate", name: "create", usage: "<name> <goal> [--strategy STRATEGY] [--description TEXT]", args: 2, setup: auditTemplateCreate},
+	{resource: "audittemplate", name: "update", usage: "<audit template> <attribute=value>...", args: 2, setup: auditTemplateUpdate},
+	{resource: "audittemplate", name: "delete", usage: "<audit template>...", args: 1, setup: auditTemplateDelete},
+
+	{resource: "actionplan", name: "list", usage: "[list flags]", setup: actionPlanList},
+	{resource: "actionplan", name: "show", usage: "<action plan>", args: 1, setup: actionPlanShow},
+	{resource: "actionplan", name: "update", usage: "<action plan> <attribute=value>...", args: 2, setup: actionPlanUpdate},
+	{resource: "actionplan", name: "delete", usage: "<action plan>...", args: 1, setup: actionPlanDelete},
+	{resource: "actionplan", name: "start", usage: "<action plan>", args: 1, setup: actionPlanStart},
+	{resource: "actionplan", name: "cancel", usage: "<action plan>", args: 1, setup: actionPlanCancel},
+
+	{resource: "action", name: "list", usage: "[--action-plan UUID] [list flags]", setup: actionList},
+	{resource: "action", name: "show", usage: "<action>", args: 1, setup: actionShow},
+
+	{resource: "goal", name: "list", usage: "[list flags]", setup: goalList},
+	{resource: "goal", name: "show", usage: "<goal>", args: 1, setup: goalShow},
+
+	{resource: "strategy", name: "list", usage: "[--goal GOAL] [list flags]", setup: strategyList},
+	{resource: "strategy", name: "show", usage: "<strategy>", args: 1, setup: strategyShow},
+
+	{resource: "service", name: "list", usage: "[list flags]", setup: serviceList},
+	{resource: "service", name: "show", usage: "<service>", args: 1, setup: serviceShow},
+
+	{resource: "datamodel", name: "list", usage: "[--type compute|storage]", setup: dataModelList},
+}
+
+// render prints a resource or a list of resources
+func render(ctx *context, v interface{}) error {
+	return formatter.Render(ctx.out, v, ctx.output)
+}
+
+// findCommand returns the command for a resource and name
+func findCommand(resource, name string) *command {
+	for i := range commands {
+		if commands[i].resource == resource && commands[i].name == name {
+			return &commands[i]
+		}
+	}
+	return nil
+}
+
+// listFlags registers the pagination and sorting flags
+func listFlags(fs *flag.FlagSet) *watcherclient.ListOptions {
+	opts := &watcherclient.ListOptions{}
+	fs.IntVar(&opts.Limit, "limit", 0, "maximum number of results")
+	fs.StringVar(&opts.Marker, "marker", "", "UUID of the last result of the previous page")
+	fs.StringVar(&opts.SortKey, "sort-key", "", "field used for sorting")
+	fs.StringVar(&opts.SortDir, "sort-dir", "", "sort direction: asc or desc")
+	return opts
+}
+
+// multiFlag collects repeated flag values
+type multiFlag []string
+
+func (m *multiFlag) String() string { return strings.Join(*m, ",") }
+
+func (m *multiFlag) Set(value string) error {
+	*m = append(*m, value)
+	return nil
+}
+
+// parseAssignments parses name=value arguments. Values are decoded as JSON
+// when possible and kept as strings otherwise.
+func parseAssignments(args []string) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	for _, arg := range args {
+		name, raw, ok := strings.Cut(arg, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid argument '%s', expected name=value", arg)
+		}
+
+		var value interface{}
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			value = raw
+		}
+		values[strings.TrimPrefix(name, "/")] = value
+	}
+	return values, nil
+}
+
+// deleteEach deletes every identifier, stopping at the first error
+func deleteEach(ids []string, del func(string) error, kind string) error {
+	for _, id := range ids {
+		if err := del(id); err != nil {
+			return fmt.Errorf("failed to delete %s %s: %w", kind, id, err)
+		}
+	}
+	return nil
+}
+
+func auditList(fs *flag.FlagSet, ctx *context) func([]string) error {
+	opts := listFlags(fs)
+	return func(args []string) error {
+		audits, err := ctx.client.ListAudits(opts)
+		if err != nil {
+			return err
+		}
+		return render(ctx, audits)
+	}
+}
+
+func auditShow(fs *flag.FlagSet, ctx *context) func([]string) error {
+	return func(args []string) error {
+		audit, err := ctx.client.GetAudit(args[0])
+		if err != nil {
+			return err
+		}
+		return render(ctx, audit)
+	}
+}
+
+func auditCreate(fs *flag.FlagSet, ctx *context) func([]string) error {
+	audit := &watcherclient.Audit{}
+	var template string
+	var params multiFlag
+	fs.StringVar(&audit.Goal, "goal", "", "goal name or UUID")
+	fs.StringVar(&audit.Strategy, "strategy", "", "strategy name or UUID")
+	fs.StringVar(&audit.AuditType, "audit-type", "ONESHOT", "ONESHOT or CONTINUOUS")
+	fs.StringVar(&audit.Interval, "interval", "", "interval of a CONTINUOUS audit, in seconds or cron format")
+	fs.StringVar(&audit.Name, "name", "", "audit name")
+	fs.BoolVar(&audit.AutoTrigger, "auto-trigger", false, "trigger the action plan automatically")
+	fs.StringVar(&template, "audit-template", "", "audit template providing goal, strategy and scope")
+	fs.Var(&params, "p", "strategy parameter as name=value, repeatable")
+
+	return func(args []string) error {
+		if template != "" {
+			t, err := ctx.client.GetAuditTemplate(template)
+			if err != nil {
+				return err
+			}
+			if audit.Goal == "" {
+				audit.Goal = t.Goal
+			}
+			if audit.Strategy == "" {
+				audit.Strategy = t.Strategy
+			}
+			audit.Scope = t.Scope
+		}
+		if audit.Goal == "" {
+			return fmt.Errorf("--goal or --audit-template is required")
+		}
+
+		parameters, err := parseAssignments(params)
+		if err != nil {
+			return err
+		}
+		if len(parameters) > 0 {
+			audit.Parameters = parameters
+		}
+
+		created, err := ctx.client.CreateAudit(audit)
+		if err != nil {
+			return err
+		}
+		return render(ctx, created)
+	}
+}
+
+func auditUpdate(fs *flag.FlagSet, ctx *context) func([]string) error {
+	return func(args []string) error {
+		updates, err := parseAssignments(args[1:])
+		if err != nil {
+			return err
+		}
+		audit, err := ctx.client.UpdateAudit(args[0], updates)
+		if err != nil {
+			return err
+		}
+		return render(ctx, audit)
+	}
+}
+
+func auditDelete(fs *flag.FlagSet, ctx *context) func([]string) error {
+	return func(args []string) error {
+		return deleteEach(args, ctx.client.DeleteAudit, "audit")
+	}
+}
+
+func auditTemplateList(fs *flag.FlagSet, ctx *context) func([]string) error {
+	opts := listFlags(fs)
+	return func(args []string) error {
+		templates, err := ctx.client.ListAuditTemplates(opts)
+		if err != nil {
+			return err
+		}
+		return render(ctx, templates)
+	}
+}
+
+func auditTemplateShow(fs *flag.FlagSet, ctx *context) func([]string) error {
+	return func(args []string) error {
+		template, err := ctx.client.GetAuditTemplate(args[0])
+		if err != nil {
+			return err
+		}
+		return render(ctx, template)
+	}
+}
+
+func auditTemplateCreate(fs *flag.FlagSet, ctx *context) func([]string) error {
+	template := &watcherclient.AuditTemplate{}
+	fs.StringVar(&template.Strategy, "strategy", "", "strategy name or UUID")
+	fs.StringVar(&template.Description, "description", "", "description")
+
+	return func(args []string) error {
+		template.Name = args[0]
+		template.Goal = args[1]
+		created, err := ctx.client.CreateAuditTemplate(template)
+		if err != nil {
+			return err
+		}
+		return render(ctx, created)
+	}
+}
+
+func auditTemplateUpdate(fs *flag.FlagSet, ctx *context) func([]string) error {
+	return func(args []string) error {
+		updates, err := parseAssignments(args[1:])
+		if err != nil {
+			return err
+		}
+		template, err := ctx.client.UpdateAuditTemplate(args[0], updates)
+		if err != nil {
+			return err
+		}
+		return render(ctx, template)
+	}
+}
+
+func auditTemplateDelete(fs *flag.FlagSet, ctx *context) func([]string) error {
+	return func(args []string) error {
+		return deleteEach(args, ctx.client.DeleteAuditTemplate, "audit template")
+	}
+}
+
+func actionPlanList(fs *flag.FlagSet, ctx *context) func([]string) error {
+	opts := listFlags(fs)
+	return func(args []string) error {
+		plans, err := ctx.client.ListActionPlans(opts)
+		if err != nil {
+			return err
+		}
+		return render(ctx, plans)
+	}
+}
+
+func actionPlanShow(fs *flag.FlagSet, ctx *context) func([]string) error {
+	return func(args []string) error {
+		plan, err := ctx.client.GetActionPlan(args[0])
+		if err != nil {
+			return err
+		}
+		return render(ctx, plan)
+	}
+}
+
+func actionPlanUpdate(fs *flag.FlagSet, ctx *context) func([]string) error {
+	return func(args []string) error {
+		updates, err := parseAssignments(args[1:])
+		if err != nil {
+			return err
+		}
+		plan, err := ctx.client.UpdateActionPlan(args[0], updates)
+		if err != nil {
+			return err
+		}
+		return render(ctx, plan)
+	}
+}
+
+func actionPlanDelete(fs *flag.FlagSet, ctx *context) func([]string) error {
+	return func(args []string) error {
+		return deleteEach(args, ctx.client.DeleteActionPlan, "action plan")
+	}
+}
+
+func actionPlanStart(fs *flag.FlagSet, ctx *context) func([]string) error {
+	return func(args []string) error {
+		plan, err := ctx.client.StartActionPlan(args[0])
+		if err != nil {
+			return err
+		}
+		return render(ctx, plan)
+	}
+}
+
+func actionPlanCancel(fs *flag.FlagSet, ctx *context) func([]string) error {
+	return func(args []string) error {
+		plan, err := ctx.client.CancelActionPlan(args[0])
+		if err != nil {
+			return err
+		}
+		return render(ctx, plan)
+	}
+}
+
+func actionList(fs *flag.FlagSet, ctx *context) func([]string) error {
+	opts := listFlags(fs)
+	var plan string
+	fs.StringVar(&plan, "action-plan", "", "only list the actions of this action plan")
+
+	return func(args []string) error {
+		var actions []watcherclient.Action
+		var err error
+		if plan != "" {
+			actions, err = ctx.client.ListActionsByActionPlan(plan)
+		} else {
+			actions, err = ctx.client.ListActions(opts)
+		}
+		if err != nil {
+			return err
+		}
+		return render(ctx, actions)
+	}
+}
+
+func actionShow(fs *flag.FlagSet, ctx *context) func([]string) error {
+	return func(args []string) error {
+		action, err := ctx.client.GetAction(args[0])
+		if err != nil {
+			return err
+		}
+		return render(ctx, action)
+	}
+}
+
+func goalList(fs *flag.FlagSet, ctx *context) func([]string) error {
+	opts := listFlags(fs)
+	return func(args []string) error {
+		goals, err := ctx.client.ListGoals(opts)
+		if err != nil {
+			return err
+		}
+		return render(ctx, goals)
+	}
+}
+
+func goalShow(fs *flag.FlagSet, ctx *context) func([]string) error {
+	return func(args []string) error {
+		goal, err := ctx.client.GetGoal(args[0])
+		if err != nil {
+			return err
+		}
+		return render(ctx, goal)
+	}
+}
+
+func strategyList(fs *flag.FlagSet, ctx *context) func([]string) error {
+	opts := listFlags(fs)
+	var goal string
+	fs.StringVar(&goal, "goal", "", "only list the strategies of this goal")
+
+	return func(args []string) error {
+		var strategies []watcherclient.Strategy
+		var err error
+		if goal != "" {
+			strategies, err = ctx.client.ListStrategiesByGoal(goal)
+		} else {
+			strategies, err = ctx.client.ListStrategies(opts)
+		}
+		if err != nil {
+			return err
+		}
+		return render(ctx, strategies)
+	}
+}
+
+func strategyShow(fs *flag.FlagSet, ctx *context) func([]string) error {
+	return func(args []string) error {
+		strategy, err := ctx.client.GetStrategy(args[0])
+		if err != nil {
+			return err
+		}
+		return render(ctx, strategy)
+	}
+}
+
+func serviceList(fs *flag.FlagSet, ctx *context) func([]string) error {
+	opts := listFlags(fs)
+	return func(args []string) error {
+		services, err := ctx.client.ListServices(opts)
+		if err != nil {
+			return err
+		}
+		return render(ctx, services)
+	}
+}
+
+func serviceShow(fs *flag.FlagSet, ctx *context) func([]string) error {
+	return func(args []string) error {
+		service, err := ctx.client.GetService(args[0])
+		if err != nil {
+			return err
+		}
+		return render(ctx, service)
+	}
+}
+
+func dataModelList(fs *flag.FlagSet, ctx *context) func([]string) error {
+	var dataModelType string
+	fs.StringVar(&dataModelType, "type", watcherclient.DataModelTypeCompute, "data model type: compute or storage")
+
+	return func(args []string) error {
+		if dataModelType != watcherclient.DataModelTypeCompute && dataModelType != watcherclient.DataModelTypeStorage {
+			return fmt.Errorf("unknown data model type %q: expected %s or %s",
+				dataModelType, watcherclient.DataModelTypeCompute, watcherclient.DataModelTypeStorage)
+		}
+
+		dm, err := ctx.client.GetDataModel(dataModelType)
+		if err != nil {
+			return err
+		}
+
+		opts := ctx.output
+		if len(opts.Columns) == 0 {
+			opts.Columns = computeModelColumns
+			if dataModelType == watcherclient.DataModelTypeStorage {
+				opts.Columns = storageModelColumns
+			}
+		}
+		if dm.Context == nil {
+			dm.Context = []map[string]interface{}{}
+		}
+		return formatter.Render(ctx.out, dm.Context, opts)
+	}
+}
@@ -0,0 +1,390 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+	"github.com/overwatch144/golang-watcherclient/watcherclient/formatter"
+)
+
+// Environment variables read by the CLI, in addition to the OS_* variables
+// of the settings below
+const (
+	envConfig   = "WATCHER_CONFIG"   // Path of the config file
+	envEndpoint = "WATCHER_ENDPOINT" // Watcher endpoint overriding the catalog
+)
+
+// setting maps a global flag to its environment variable
+type setting struct {
+	flag  string
+	env   []string // Variables checked in order
+	usage string
+	value *string
+}
+
+// config holds the connection settings of the CLI
+type config struct {
+	Cloud                       string
+	AuthType                    string
+	AuthURL                     string
+	Username                    string
+	Password                    string
+	ProjectName                 string
+	ProjectID                   string
+	ProjectDomainID             string
+	ProjectDomainName           string
+	UserDomainID                string
+	UserDomainName              string
+	DomainID                    string
+	DomainName                  string
+	SystemScope                 string
+	ApplicationCredentialID     string
+	ApplicationCredentialName   string
+	ApplicationCredentialSecret string
+	Region                      string
+	Interface                   string
+	ServiceName                 string
+	CACert                      string
+	ClientCert                  string
+	ClientKey                   string
+	Proxy                       string
+	Insecure                    bool
+	Token                       string
+	TokenCommand                string
+	Passcode                    string
+	TokenCache                  string
+	TokenCacheKey               string
+	Endpoint                    string
+	ConfigFile                  string
+	Timeout                     time.Duration
+	Format                      string
+	Columns                     multiFlag
+	Template                    string
+	NoHeaders                   bool
+	Debug                       bool
+
+	logger *slog.Logger // Set by --debug
+}
+
+// settings returns the settings resolved from flags, environment and the
+// config file
+func (c *config) settings() []setting {
+	return []setting{
+		{flag: "os-cloud", env: []string{"OS_CLOUD"}, usage: "cloud of clouds.yaml", value: &c.Cloud},
+		{flag: "os-auth-type", env: []string{"OS_AUTH_TYPE"}, usage: "none for a Watcher running with noauth", value: &c.AuthType},
+		{flag: "os-auth-url", env: []string{"OS_AUTH_URL"}, usage: "Keystone v3 URL", value: &c.AuthURL},
+		{flag: "os-username", env: []string{"OS_USERNAME"}, usage: "user name", value: &c.Username},
+		{flag: "os-password", env: []string{"OS_PASSWORD"}, usage: "password", value: &c.Password},
+		{flag: "os-project-name", env: []string{"OS_PROJECT_NAME", "OS_TENANT_NAME"}, usage: "project name", value: &c.ProjectName},
+		{flag: "os-project-id", env: []string{"OS_PROJECT_ID", "OS_TENANT_ID"}, usage: "project ID", value: &c.ProjectID},
+		{flag: "os-project-domain-id", env: []string{"OS_PROJECT_DOMAIN_ID"}, usage: "project domain ID", value: &c.ProjectDomainID},
+		{flag: "os-project-domain-name", env: []string{"OS_PROJECT_DOMAIN_NAME"}, usage: "project domain name", value: &c.ProjectDomainName},
+		{flag: "os-user-domain-id", env: []string{"OS_USER_DOMAIN_ID"}, usage: "user domain ID", value: &c.UserDomainID},
+		{flag: "os-user-domain-name", env: []string{"OS_USER_DOMAIN_NAME"}, usage: "user domain name", value: &c.UserDomainName},
+		{flag: "os-domain-id", env: []string{"OS_DOMAIN_ID"}, usage: "domain ID of a domain-scoped token", value: &c.DomainID},
+		{flag: "os-domain-name", env: []string{"OS_DOMAIN_NAME"}, usage: "domain name of a domain-scoped token", value: &c.DomainName},
+		{flag: "os-system-scope", env: []string{"OS_SYSTEM_SCOPE"}, usage: "system scope, e.g. all", value: &c.SystemScope},
+		{flag: "os-application-credential-id", env: []string{"OS_APPLICATION_CREDENTIAL_ID"}, usage: "application credential ID", value: &c.ApplicationCredentialID},
+		{flag: "os-application-credential-name", env: []string{"OS_APPLICATION_CREDENTIAL_NAME"}, usage: "application credential name", value: &c.ApplicationCredentialName},
+		{flag: "os-application-credential-secret", env: []string{"OS_APPLICATION_CREDENTIAL_SECRET"}, usage: "application credential secret", value: &c.ApplicationCredentialSecret},
+		{flag: "os-region-name", env: []string{"OS_REGION_NAME"}, usage: "region name", value: &c.Region},
+		{flag: "os-interface", env: []string{"OS_INTERFACE"}, usage: "catalog interface: public, internal or admin", value: &c.Interface},
+		{flag: "os-service-name", env: []string{"OS_INFRA_OPTIM_SERVICE_NAME"}, usage: "catalog name of the Watcher service", value: &c.ServiceName},
+		{flag: "os-cacert", env: []string{"OS_CACERT"}, usage: "CA bundle used to verify TLS certificates", value: &c.CACert},
+		{flag: "os-cert", env: []string{"OS_CERT"}, usage: "client certificate for mutual TLS", value: &c.ClientCert},
+		{flag: "os-key", env: []string{"OS_KEY"}, usage: "key of the client certificate", value: &c.ClientKey},
+		{flag: "proxy", env: []string{"WATCHER_PROXY"}, usage: "HTTP proxy URL, defaults to HTTPS_PROXY", value: &c.Proxy},
+		{flag: "os-token", env: []string{"OS_TOKEN"}, usage: "existing token, used with --watcher-endpoint or --os-auth-url", value: &c.Token},
+		{flag: "os-passcode", env: []string{"OS_PASSCODE"}, usage: "TOTP passcode sent with the password", value: &c.Passcode},
+		{flag: "token-command", env: []string{"WATCHER_TOKEN_COMMAND"}, usage: "command printing a Keystone token, e.g. a federated login helper", value: &c.TokenCommand},
+		{flag: "token-cache", env: []string{"WATCHER_TOKEN_CACHE"}, usage: "directory caching tokens between invocations", value: &c.TokenCache},
+		{flag: "token-cache-key", env: []string{"WATCHER_TOKEN_CACHE_KEY"}, usage: "passphrase encrypting the token cache", value: &c.TokenCacheKey},
+		{flag: "watcher-endpoint", env: []string{envEndpoint, "OS_INFRA_OPTIM_ENDPOINT_OVERRIDE"}, usage: "Watcher endpoint used instead of the catalog", value: &c.Endpoint},
+	}
+}
+
+// register adds the global flags to fs
+func (c *config) register(fs *flag.FlagSet) {
+	for _, s := range c.settings() {
+		fs.StringVar(s.value, s.flag, "", fmt.Sprintf("%s (env %s)", s.usage, s.env[0]))
+	}
+	fs.StringVar(&c.ConfigFile, "config", "", "openrc-style config file (env "+envConfig+")")
+	fs.BoolVar(&c.Insecure, "insecure", false, "skip TLS verification (env OS_INSECURE)")
+	fs.BoolVar(&c.Debug, "debug", false, "log requests and authentication events to stderr")
+	fs.DurationVar(&c.Timeout, "timeout", watcherclient.DefaultTimeout, "HTTP timeout")
+	fs.StringVar(&c.Format, "f", string(formatter.FormatTable), "output format: table, json, yaml, csv or template")
+	fs.Var(&c.Columns, "c", "column to include, repeatable")
+	fs.StringVar(&c.Template, "template", "", "Go template used with -f template")
+	fs.BoolVar(&c.NoHeaders, "no-headers", false, "omit table and CSV headers")
+}
+
+// resolve fills the settings not given as flags from the environment, then
+// from the config file
+func (c *config) resolve(getenv func(string) string) error {
+	file := map[string]string{}
+
+	path := c.ConfigFile
+	if path == "" {
+		path = getenv(envConfig)
+	}
+	if path == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			if candidate := filepath.Join(home, ".config", "watcher", "openrc"); fileExists(candidate) {
+				path = candidate
+			}
+		}
+	}
+	if path != "" {
+		values, err := readConfigFile(path)
+		if err != nil {
+			return err
+		}
+		file = values
+	}
+
+	if !c.Insecure {
+		insecure := getenv("OS_INSECURE")
+		if insecure == "" {
+			insecure = file["OS_INSECURE"]
+		}
+		c.Insecure, _ = strconv.ParseBool(insecure)
+	}
+
+	for _, s := range c.settings() {
+		if *s.value != "" {
+			continue
+		}
+		for _, name := range s.env {
+			if v := getenv(name); v != "" {
+				*s.value = v
+				break
+			}
+			if v := file[name]; v != "" {
+				*s.value = v
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// output returns the formatter options selected by the global flags
+func (c *config) output() (formatter.Options, error) {
+	format, err := formatter.ParseFormat(c.Format)
+	if err != nil {
+		return formatter.Options{}, err
+	}
+	return formatter.Options{
+		Format:    format,
+		Columns:   c.Columns,
+		Template:  c.Template,
+		NoHeaders: c.NoHeaders,
+	}, nil
+}
+
+// newClient creates a client from the resolved settings
+func (c *config) newClient() (*watcherclient.Client, error) {
+	var tokenProvider func() (string, error)
+	if fields := strings.Fields(c.TokenCommand); len(fields) > 0 {
+		tokenProvider = watcherclient.CommandTokenProvider(fields[0], fields[1:]...)
+
+		// Without Keystone, the command provides the token used as is
+		if c.Token == "" && c.AuthURL == "" && c.Cloud == "" {
+			token, err := tokenProvider()
+			if err != nil {
+				return nil, err
+			}
+			c.Token = token
+		}
+	}
+
+	if c.AuthType == "none" || c.AuthType == "noauth" {
+		if c.Endpoint == "" {
+			return nil, fmt.Errorf("--watcher-endpoint or %s is required with --os-auth-type %s", envEndpoint, c.AuthType)
+		}
+		return watcherclient.NewClientNoAuthWithOptions(watcherclient.ClientOptions{
+			EndpointOverride: c.Endpoint,
+			ProjectID:        c.ProjectID,
+			CACert:           c.CACert,
+			ClientCert:       c.ClientCert,
+			ClientKey:        c.ClientKey,
+			Insecure:         c.Insecure,
+			Proxy:            c.Proxy,
+			Timeout:          c.Timeout,
+			Logger:           c.logger,
+		})
+	}
+
+	if c.Token != "" && c.AuthURL == "" && c.Cloud == "" {
+		if c.Endpoint == "" {
+			return nil, fmt.Errorf("--watcher-endpoint or %s is required with a token", envEndpoint)
+		}
+		return c.directClient(watcherclient.NewTokenAuthenticator(c.endpoint(), c.Token))
+	}
+
+	var cache watcherclient.TokenCache
+	if c.TokenCache != "" {
+		fileCache, err := watcherclient.NewFileTokenCache(c.TokenCache, c.TokenCacheKey)
+		if err != nil {
+			return nil, err
+		}
+		cache = fileCache
+	}
+
+	if c.AuthURL == "" && c.Cloud != "" {
+		opts, err := watcherclient.ClientOptionsFromCloud(c.Cloud)
+		if err != nil {
+			return nil, err
+		}
+		opts.Timeout = c.Timeout
+		if c.Region != "" {
+			opts.Region = c.Region
+		}
+		if c.Interface != "" {
+			opts.Interface = c.Interface
+		}
+		if c.CACert != "" {
+			opts.CACert = c.CACert
+		}
+		if c.ServiceName != "" {
+			opts.ServiceName = c.ServiceName
+		}
+		if c.ClientCert != "" {
+			opts.ClientCert, opts.ClientKey = c.ClientCert, c.ClientKey
+		}
+		opts.Insecure = opts.Insecure || c.Insecure
+		opts.Proxy = c.Proxy
+		opts.TokenCache = cache
+		opts.Logger = c.logger
+		if tokenProvider != nil {
+			opts.TokenProvider = tokenProvider
+		}
+		if c.Passcode != "" {
+			opts.Passcode = c.Passcode
+		}
+		if c.Endpoint != "" {
+			opts.EndpointOverride = c.Endpoint
+		}
+		return watcherclient.NewClient(opts)
+	}
+
+	if c.AuthURL == "" {
+		return nil, fmt.Errorf("missing credentials: set OS_CLOUD, OS_AUTH_URL or OS_TOKEN, or use --config")
+	}
+
+	return watcherclient.NewClient(watcherclient.ClientOptions{
+		AuthURL:                     c.AuthURL,
+		Username:                    c.Username,
+		Password:                    c.Password,
+		ProjectName:                 c.ProjectName,
+		ProjectID:                   c.ProjectID,
+		ProjectDomainID:             c.ProjectDomainID,
+		ProjectDomainName:           c.ProjectDomainName,
+		UserDomainID:                c.UserDomainID,
+		UserDomainName:              c.UserDomainName,
+		DomainID:                    c.DomainID,
+		DomainName:                  c.DomainName,
+		SystemScope:                 c.SystemScope,
+		Token:                       c.Token,
+		TokenProvider:               tokenProvider,
+		Passcode:                    c.Passcode,
+		ApplicationCredentialID:     c.ApplicationCredentialID,
+		ApplicationCredentialName:   c.ApplicationCredentialName,
+		ApplicationCredentialSecret: c.ApplicationCredentialSecret,
+		Region:                      c.Region,
+		Interface:                   c.Interface,
+		ServiceName:                 c.ServiceName,
+		EndpointOverride:            c.Endpoint,
+		CACert:                      c.CACert,
+		ClientCert:                  c.ClientCert,
+		ClientKey:                   c.ClientKey,
+		Insecure:                    c.Insecure,
+		Proxy:                       c.Proxy,
+		TokenCache:                  cache,
+		Logger:                      c.logger,
+		Timeout:                     c.Timeout,
+		AllowReauth:                 true,
+	})
+}
+
+// endpoint returns the Watcher endpoint without API version
+func (c *config) endpoint() string {
+	endpoint := strings.TrimSuffix(c.Endpoint, "/")
+	return strings.TrimSuffix(endpoint, "/"+watcherclient.DefaultAPIVersion)
+}
+
+// directClient creates a client talking to the Watcher endpoint without
+// Keystone
+func (c *config) directClient(auth interface {
+	GetToken() (string, error)
+	GetEndpoint() string
+}) (*watcherclient.Client, error) {
+	transport, err := watcherclient.NewTransport(watcherclient.ClientOptions{
+		CACert:     c.CACert,
+		ClientCert: c.ClientCert,
+		ClientKey:  c.ClientKey,
+		Insecure:   c.Insecure,
+		Proxy:      c.Proxy,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	client := watcherclient.NewClientWithAuthenticator(auth)
+	client.SetTimeout(c.Timeout)
+	client.SetLogger(c.logger)
+	if transport != nil {
+		client.SetTransport(transport)
+	}
+	return client, nil
+}
+
+// readConfigFile parses KEY=VALUE lines, accepting the "export" prefix and
+// quoting of openrc files
+func readConfigFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open config file: %w", err)
+	}
+	defer f.Close()
+
+	values := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected KEY=VALUE", path, n)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		values[strings.TrimSpace(key)] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	return values, nil
+}
+
+// fileExists reports whether path is an existing file
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
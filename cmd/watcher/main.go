@@ -0,0 +1,150 @@
+// Command watcher is a command-line client for the OpenStack Watcher API.
+//
+// Usage:
+//
+//	watcher [global flags] <resource> <command> [flags] [arguments]
+//
+// Credentials are read from flags, then from the OS_* environment
+// variables, then from an openrc-style config file given with --config,
+// WATCHER_CONFIG or found at ~/.config/watcher/openrc.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+	"github.com/overwatch144/golang-watcherclient/watcherclient/formatter"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Getenv, os.Stdout, os.Stderr))
+}
+
+// run executes the CLI and returns the exit status
+func run(args []string, getenv func(string) string, stdout, stderr io.Writer) int {
+	var cfg config
+	global := flag.NewFlagSet("watcher", flag.ContinueOnError)
+	global.SetOutput(stderr)
+	cfg.register(global)
+	global.Usage = func() { usage(global, stderr) }
+
+	if err := global.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+
+	rest := global.Args()
+	if len(rest) < 2 {
+		usage(global, stderr)
+		return 2
+	}
+
+	cmd := findCommand(rest[0], rest[1])
+	if cmd == nil {
+		fmt.Fprintf(stderr, "ERROR: unknown command '%s %s'\n", rest[0], rest[1])
+		usage(global, stderr)
+		return 2
+	}
+
+	output, err := cfg.output()
+	if err != nil {
+		fmt.Fprintf(stderr, "ERROR: %v\n", err)
+		return 2
+	}
+
+	fs := flag.NewFlagSet(cmd.resource+" "+cmd.name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: watcher %s %s %s\n", cmd.resource, cmd.name, cmd.usage)
+		fs.PrintDefaults()
+	}
+	ctx := &context{out: stdout, output: output}
+	exec := cmd.setup(fs, ctx)
+
+	positional, err := parseInterleaved(fs, rest[2:])
+	if err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if len(positional) < cmd.args {
+		fs.Usage()
+		return 2
+	}
+
+	if err := cfg.resolve(getenv); err != nil {
+		fmt.Fprintf(stderr, "ERROR: %v\n", err)
+		return 1
+	}
+	if cfg.Debug {
+		cfg.logger = slog.New(slog.NewTextHandler(stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	}
+	client, err := cfg.newClient()
+	if err != nil {
+		fmt.Fprintf(stderr, "ERROR: %v\n", err)
+		return 1
+	}
+	ctx.client = client
+
+	if err := exec(positional); err != nil {
+		fmt.Fprintf(stderr, "ERROR: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// context is passed to the commands
+type context struct {
+	client watcherclient.API
+	out    io.Writer
+	output formatter.Options
+}
+
+// parseInterleaved parses flags placed before, between or after the
+// positional arguments
+func parseInterleaved(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// usage prints the global usage and the available commands
+func usage(global *flag.FlagSet, w io.Writer) {
+	fmt.Fprintln(w, "Usage: watcher [global flags] <resource> <command> [flags] [arguments]")
+	fmt.Fprintln(w, "\nCommands:")
+
+	byResource := map[string][]string{}
+	for _, cmd := range commands {
+		byResource[cmd.resource] = append(byResource[cmd.resource], cmd.name)
+	}
+	resources := make([]string, 0, len(byResource))
+	for resource := range byResource {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+	for _, resource := range resources {
+		fmt.Fprintf(w, "  %-14s %s\n", resource, strings.Join(byResource[resource], ", "))
+	}
+
+	fmt.Fprintln(w, "\nGlobal flags:")
+	global.SetOutput(w)
+	global.PrintDefaults()
+}
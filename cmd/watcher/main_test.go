@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient/watchertest"
+)
+
+// runCLI runs the CLI with the given environment and returns its output
+func runCLI(t *testing.T, env map[string]string, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := run(args, func(name string) string { return env[name] }, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+// Test 1: Audit and action plan commands against the fake server
+func TestAuditCommands(t *testing.T) {
+	clock := watchertest.NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	server := watchertest.NewServer(watchertest.Options{Clock: clock})
+	defer server.Close()
+
+	opts := server.ClientOptions()
+	env := map[string]string{
+		"OS_AUTH_URL":          opts.AuthURL,
+		"OS_USERNAME":          opts.Username,
+		"OS_PASSWORD":          opts.Password,
+		"OS_PROJECT_NAME":      opts.ProjectName,
+		"OS_PROJECT_DOMAIN_ID": opts.ProjectDomainID,
+		"OS_USER_DOMAIN_ID":    opts.UserDomainID,
+	}
+
+	code, out, stderr := runCLI(t, env, "-f", "json", "audit", "create", "--goal", "dummy", "-p", "period=3600", "--name", "nightly")
+	if code != 0 {
+		t.Fatalf("Expected exit status 0, got %d: %s", code, stderr)
+	}
+
+	var audit map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &audit); err != nil {
+		t.Fatalf("Failed to parse output: %v", err)
+	}
+	parameters, _ := audit["Parameters"].(map[string]interface{})
+	if audit["Name"] != "nightly" || parameters["period"] != float64(3600) {
+		t.Errorf("Expected audit nightly with period 3600, got %v", audit)
+	}
+	uuid, _ := audit["UUID"].(string)
+
+	clock.Advance(time.Second + watchertest.DefaultAuditDuration)
+
+	code, out, _ = runCLI(t, env, "audit", "list")
+	if code != 0 || uuid == "" || !strings.Contains(out, uuid) || !strings.Contains(out, "SUCCEEDED") {
+		t.Errorf("Expected succeeded audit in list, got %d: %s", code, out)
+	}
+
+	_, out, _ = runCLI(t, env, "-f", "csv", "--no-headers", "-c", "UUID", "actionplan", "list")
+	plans := strings.Fields(out)
+	if len(plans) != 1 {
+		t.Fatalf("Expected one action plan, got %q", out)
+	}
+
+	code, out, stderr = runCLI(t, env, "actionplan", "start", plans[0])
+	if code != 0 || !strings.Contains(out, "TRIGGERED") {
+		t.Errorf("Expected TRIGGERED action plan, got %d: %s%s", code, out, stderr)
+	}
+
+	code, out, _ = runCLI(t, env, "action", "list", "--action-plan", plans[0])
+	if code != 0 || !strings.Contains(out, "nop") {
+		t.Errorf("Expected nop action, got %d: %s", code, out)
+	}
+
+	if code, _, stderr := runCLI(t, env, "audit", "show", "missing"); code != 1 || !strings.Contains(stderr, "ERROR") {
+		t.Errorf("Expected error for missing audit, got %d: %s", code, stderr)
+	}
+}
+
+// Test 2: Token authentication from a config file
+func TestConfigFile(t *testing.T) {
+	server := watchertest.NewServer(watchertest.Options{})
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "openrc")
+	content := "# Watcher credentials\n" +
+		"export OS_TOKEN=\"" + server.Token() + "\"\n" +
+		"WATCHER_ENDPOINT='" + server.Endpoint() + "'\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	code, out, stderr := runCLI(t, map[string]string{"WATCHER_CONFIG": path}, "-f", "csv", "goal", "list", "--limit", "2")
+	if code != 0 {
+		t.Fatalf("Expected exit status 0, got %d: %s", code, stderr)
+	}
+	if lines := strings.Split(strings.TrimSpace(out), "\n"); len(lines) != 3 {
+		t.Errorf("Expected header and 2 goals, got %q", out)
+	}
+
+	code, out, _ = runCLI(t, map[string]string{"WATCHER_CONFIG": path}, "service", "show", "watcher-applier")
+	if code != 0 || !strings.Contains(out, "ACTIVE") {
+		t.Errorf("Expected active service, got %d: %s", code, out)
+	}
+}
+
+// Test 3: Usage errors
+func TestUsage(t *testing.T) {
+	if code, _, stderr := runCLI(t, nil, "audit", "frobnicate"); code != 2 || !strings.Contains(stderr, "unknown command") {
+		t.Errorf("Expected unknown command error, got %d: %s", code, stderr)
+	}
+
+	if code, _, stderr := runCLI(t, nil, "goal", "list"); code != 1 || !strings.Contains(stderr, "missing credentials") {
+		t.Errorf("Expected missing credentials error, got %d: %s", code, stderr)
+	}
+
+	values, err := parseAssignments([]string{"name=foo", "auto_trigger=true", "/interval=60"})
+	if err != nil {
+		t.Fatalf("parseAssignments failed: %v", err)
+	}
+	if values["name"] != "foo" || values["auto_trigger"] != true || values["interval"] != float64(60) {
+		t.Errorf("Unexpected assignments %v", values)
+	}
+}
+
+// Test 4: Noauth mode
+func TestNoAuth(t *testing.T) {
+	server := watchertest.NewServer(watchertest.Options{NoAuth: true})
+	defer server.Close()
+
+	env := map[string]string{"OS_AUTH_TYPE": "none", "WATCHER_ENDPOINT": server.Endpoint() + "/v1"}
+	code, out, stderr := runCLI(t, env, "goal", "list")
+	if code != 0 || !strings.Contains(out, "dummy") {
+		t.Errorf("Expected goals without authentication, got %d: %s%s", code, out, stderr)
+	}
+
+	code, _, stderr = runCLI(t, env, "datamodel", "list", "--type", "network")
+	if code != 1 || !strings.Contains(stderr, "unknown data model type") {
+		t.Errorf("Expected unknown data model type error, got %d: %s", code, stderr)
+	}
+
+	if code, _, _ := runCLI(t, map[string]string{"OS_AUTH_TYPE": "none"}, "goal", "list"); code == 0 {
+		t.Error("Expected error without endpoint")
+	}
+}
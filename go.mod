@@ -4,5 +4,7 @@ go 1.22
 
 toolchain go1.24.3
 
-require github.com/gophercloud/gophercloud/v2 v2.8.0
-
+require (
+	github.com/gophercloud/gophercloud/v2 v2.8.0
+	gopkg.in/yaml.v2 v2.4.0
+)
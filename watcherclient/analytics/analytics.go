@@ -0,0 +1,399 @@
+// Package analytics computes utilization reports over the Watcher compute
+// data model, so that recommendations can be checked against the same
+// model Watcher uses.
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+)
+
+// Report aggregates every analysis of a compute data model
+type Report struct {
+	Hosts          []HostUtilization
+	EmptyHosts     []string
+	DrainableHosts []string
+	Density        []ZoneDensity
+	Fragmentation  Fragmentation
+	Summary        Summary
+}
+
+// HostUtilization represents the resource usage of a compute node.
+// Capacities take the reserved resources and allocation ratios into account.
+type HostUtilization struct {
+	NodeUUID         string
+	Hostname         string
+	AvailabilityZone string
+	State            string
+	Status           string
+	Instances        int
+
+	PhysicalVCPUs  int
+	PhysicalMemory int // MB
+	PhysicalDisk   int // GB
+
+	VCPUsUsed      int
+	VCPUsCapacity  float64
+	MemoryUsed     int // MB
+	MemoryCapacity float64
+	DiskUsed       int // GB
+	DiskCapacity   float64
+
+	VCPUUtilization   float64 // 0-1 of the effective capacity
+	MemoryUtilization float64
+	DiskUtilization   float64
+
+	VCPUOvercommit   float64 // Used over physical resources
+	MemoryOvercommit float64
+	DiskOvercommit   float64
+}
+
+// Schedulable reports whether new instances can be placed on the host
+func (h HostUtilization) Schedulable() bool {
+	return h.State != "down" && h.Status != "disabled"
+}
+
+// FreeVCPUs returns the remaining vCPU capacity
+func (h HostUtilization) FreeVCPUs() float64 {
+	return h.VCPUsCapacity - float64(h.VCPUsUsed)
+}
+
+// FreeMemory returns the remaining memory capacity in MB
+func (h HostUtilization) FreeMemory() float64 {
+	return h.MemoryCapacity - float64(h.MemoryUsed)
+}
+
+// FreeDisk returns the remaining disk capacity in GB
+func (h HostUtilization) FreeDisk() float64 {
+	return h.DiskCapacity - float64(h.DiskUsed)
+}
+
+// ZoneDensity represents the instance density of an availability zone
+type ZoneDensity struct {
+	Zone             string
+	Hosts            int
+	Instances        int
+	InstancesPerHost float64
+}
+
+// Fragmentation measures how scattered free capacity is over schedulable
+// hosts: 0 means all free capacity is on one host, values close to 1 mean
+// it is spread thinly across many hosts.
+type Fragmentation struct {
+	VCPU   float64
+	Memory float64
+	Disk   float64
+}
+
+// Stats holds summary statistics of a utilization series
+type Stats struct {
+	Min    float64
+	Max    float64
+	Mean   float64
+	StdDev float64
+}
+
+// Summary holds cluster-wide statistics
+type Summary struct {
+	Hosts             int
+	Instances         int
+	VCPU              Stats
+	Memory            Stats
+	Disk              Stats
+	VCPUOvercommit    float64 // Cluster-wide used over physical vCPUs
+	MemoryOvercommit  float64
+	DiskOvercommit    float64
+	SchedulableHosts  int
+	OverCapacityHosts []string
+}
+
+// AnalyzeClient fetches the compute data model and analyzes it
+func AnalyzeClient(c *watcherclient.Client) (*Report, error) {
+	model, err := c.GetComputeDataModel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get compute data model: %w", err)
+	}
+	return Analyze(model), nil
+}
+
+// Analyze produces every report for the given compute data model
+func Analyze(m *watcherclient.ComputeDataModel) *Report {
+	hosts := Utilization(m)
+	return &Report{
+		Hosts:          hosts,
+		EmptyHosts:     EmptyHosts(hosts),
+		DrainableHosts: DrainableHosts(m),
+		Density:        DensityByZone(hosts),
+		Fragmentation:  Fragment(hosts),
+		Summary:        Summarize(hosts),
+	}
+}
+
+// Utilization computes per-host utilization, sorted by hostname
+func Utilization(m *watcherclient.ComputeDataModel) []HostUtilization {
+	hosts := make([]HostUtilization, 0, len(m.Nodes))
+	index := make(map[string]int, len(m.Nodes))
+
+	for _, node := range m.Nodes {
+		index[node.UUID] = len(hosts)
+		hosts = append(hosts, HostUtilization{
+			NodeUUID:         node.UUID,
+			Hostname:         node.Hostname,
+			AvailabilityZone: node.AvailabilityZone,
+			State:            node.State,
+			Status:           node.Status,
+			PhysicalVCPUs:    node.VCPUs,
+			PhysicalMemory:   node.Memory,
+			PhysicalDisk:     node.Disk,
+			VCPUsCapacity:    capacity(node.VCPUs, node.VCPUReserved, node.VCPURatio),
+			MemoryCapacity:   capacity(node.Memory, node.MemoryMBReserved, node.MemoryRatio),
+			DiskCapacity:     capacity(node.Disk, node.DiskGBReserved, node.DiskRatio),
+		})
+	}
+
+	for _, instance := range m.Instances {
+		i, ok := index[instance.NodeUUID]
+		if !ok {
+			continue
+		}
+		hosts[i].Instances++
+		hosts[i].VCPUsUsed += instance.VCPUs
+		hosts[i].MemoryUsed += instance.Memory
+		hosts[i].DiskUsed += instance.Disk
+	}
+
+	for i := range hosts {
+		h := &hosts[i]
+		h.VCPUUtilization = ratio(float64(h.VCPUsUsed), h.VCPUsCapacity)
+		h.MemoryUtilization = ratio(float64(h.MemoryUsed), h.MemoryCapacity)
+		h.DiskUtilization = ratio(float64(h.DiskUsed), h.DiskCapacity)
+		h.VCPUOvercommit = ratio(float64(h.VCPUsUsed), float64(h.PhysicalVCPUs))
+		h.MemoryOvercommit = ratio(float64(h.MemoryUsed), float64(h.PhysicalMemory))
+		h.DiskOvercommit = ratio(float64(h.DiskUsed), float64(h.PhysicalDisk))
+	}
+
+	sort.Slice(hosts, func(i, j int) bool {
+		return hosts[i].Hostname < hosts[j].Hostname
+	})
+
+	return hosts
+}
+
+// EmptyHosts returns the hostnames of hosts without instances
+func EmptyHosts(hosts []HostUtilization) []string {
+	var empty []string
+	for _, h := range hosts {
+		if h.Instances == 0 {
+			empty = append(empty, h.Hostname)
+		}
+	}
+	return empty
+}
+
+// DrainableHosts returns the hosts whose instances would all fit on the
+// other schedulable hosts, using first-fit decreasing placement. Each host
+// is evaluated independently against the current free capacity.
+func DrainableHosts(m *watcherclient.ComputeDataModel) []string {
+	hosts := Utilization(m)
+
+	var drainable []string
+	for _, candidate := range hosts {
+		if candidate.Instances == 0 {
+			continue
+		}
+
+		instances := m.InstancesOn(candidate.NodeUUID)
+		sort.Slice(instances, func(i, j int) bool {
+			if instances[i].VCPUs != instances[j].VCPUs {
+				return instances[i].VCPUs > instances[j].VCPUs
+			}
+			return instances[i].Memory > instances[j].Memory
+		})
+
+		var targets []*bin
+		for _, h := range hosts {
+			if h.NodeUUID == candidate.NodeUUID || !h.Schedulable() {
+				continue
+			}
+			targets = append(targets, &bin{vcpus: h.FreeVCPUs(), memory: h.FreeMemory(), disk: h.FreeDisk()})
+		}
+
+		if fitsAll(instances, targets) {
+			drainable = append(drainable, candidate.Hostname)
+		}
+	}
+
+	return drainable
+}
+
+// DensityByZone returns the instance density of each availability zone
+func DensityByZone(hosts []HostUtilization) []ZoneDensity {
+	zones := map[string]*ZoneDensity{}
+	for _, h := range hosts {
+		d, ok := zones[h.AvailabilityZone]
+		if !ok {
+			d = &ZoneDensity{Zone: h.AvailabilityZone}
+			zones[h.AvailabilityZone] = d
+		}
+		d.Hosts++
+		d.Instances += h.Instances
+	}
+
+	density := make([]ZoneDensity, 0, len(zones))
+	for _, d := range zones {
+		d.InstancesPerHost = ratio(float64(d.Instances), float64(d.Hosts))
+		density = append(density, *d)
+	}
+
+	sort.Slice(density, func(i, j int) bool {
+		return density[i].Zone < density[j].Zone
+	})
+
+	return density
+}
+
+// Fragment computes the fragmentation of free capacity on schedulable hosts
+func Fragment(hosts []HostUtilization) Fragmentation {
+	var vcpu, memory, disk []float64
+	for _, h := range hosts {
+		if !h.Schedulable() {
+			continue
+		}
+		vcpu = append(vcpu, h.FreeVCPUs())
+		memory = append(memory, h.FreeMemory())
+		disk = append(disk, h.FreeDisk())
+	}
+
+	return Fragmentation{
+		VCPU:   fragmentation(vcpu),
+		Memory: fragmentation(memory),
+		Disk:   fragmentation(disk),
+	}
+}
+
+// Summarize computes cluster-wide statistics
+func Summarize(hosts []HostUtilization) Summary {
+	summary := Summary{Hosts: len(hosts)}
+
+	var vcpu, memory, disk []float64
+	var usedVCPUs, usedMemory, usedDisk int
+	var physVCPUs, physMemory, physDisk int
+
+	for _, h := range hosts {
+		summary.Instances += h.Instances
+		if h.Schedulable() {
+			summary.SchedulableHosts++
+		}
+		if h.VCPUUtilization > 1 || h.MemoryUtilization > 1 || h.DiskUtilization > 1 {
+			summary.OverCapacityHosts = append(summary.OverCapacityHosts, h.Hostname)
+		}
+
+		vcpu = append(vcpu, h.VCPUUtilization)
+		memory = append(memory, h.MemoryUtilization)
+		disk = append(disk, h.DiskUtilization)
+
+		usedVCPUs += h.VCPUsUsed
+		usedMemory += h.MemoryUsed
+		usedDisk += h.DiskUsed
+		physVCPUs += h.PhysicalVCPUs
+		physMemory += h.PhysicalMemory
+		physDisk += h.PhysicalDisk
+	}
+
+	summary.VCPU = stats(vcpu)
+	summary.Memory = stats(memory)
+	summary.Disk = stats(disk)
+	summary.VCPUOvercommit = ratio(float64(usedVCPUs), float64(physVCPUs))
+	summary.MemoryOvercommit = ratio(float64(usedMemory), float64(physMemory))
+	summary.DiskOvercommit = ratio(float64(usedDisk), float64(physDisk))
+
+	return summary
+}
+
+// bin is the remaining capacity of a placement target
+type bin struct {
+	vcpus  float64
+	memory float64
+	disk   float64
+}
+
+// fitsAll places the instances first-fit into the bins
+func fitsAll(instances []watcherclient.Instance, bins []*bin) bool {
+	for _, instance := range instances {
+		placed := false
+		for _, b := range bins {
+			if float64(instance.VCPUs) <= b.vcpus &&
+				float64(instance.Memory) <= b.memory &&
+				float64(instance.Disk) <= b.disk {
+				b.vcpus -= float64(instance.VCPUs)
+				b.memory -= float64(instance.Memory)
+				b.disk -= float64(instance.Disk)
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			return false
+		}
+	}
+	return true
+}
+
+// capacity returns the effective capacity of a resource
+func capacity(total, reserved int, allocationRatio float64) float64 {
+	if allocationRatio <= 0 {
+		allocationRatio = 1
+	}
+	return float64(total-reserved) * allocationRatio
+}
+
+// ratio divides safely, returning 0 for an empty denominator
+func ratio(a, b float64) float64 {
+	if b == 0 {
+		return 0
+	}
+	return a / b
+}
+
+// fragmentation returns 1 - largest free block / total free capacity
+func fragmentation(free []float64) float64 {
+	var total, largest float64
+	for _, f := range free {
+		if f <= 0 {
+			continue
+		}
+		total += f
+		largest = math.Max(largest, f)
+	}
+	if total == 0 {
+		return 0
+	}
+	return 1 - largest/total
+}
+
+// stats computes summary statistics
+func stats(values []float64) Stats {
+	if len(values) == 0 {
+		return Stats{}
+	}
+
+	s := Stats{Min: values[0], Max: values[0]}
+	var sum float64
+	for _, v := range values {
+		s.Min = math.Min(s.Min, v)
+		s.Max = math.Max(s.Max, v)
+		sum += v
+	}
+	s.Mean = sum / float64(len(values))
+
+	var variance float64
+	for _, v := range values {
+		variance += (v - s.Mean) * (v - s.Mean)
+	}
+	s.StdDev = math.Sqrt(variance / float64(len(values)))
+
+	return s
+}
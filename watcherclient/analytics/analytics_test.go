@@ -0,0 +1,95 @@
+package analytics
+
+import (
+	"testing"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+)
+
+func testModel() *watcherclient.ComputeDataModel {
+	return &watcherclient.ComputeDataModel{
+		Nodes: []watcherclient.ComputeNode{
+			{UUID: "n1", Hostname: "compute-1", AvailabilityZone: "az1", State: "up", Status: "enabled", VCPUs: 16, VCPURatio: 2, Memory: 32768, Disk: 500},
+			{UUID: "n2", Hostname: "compute-2", AvailabilityZone: "az1", State: "up", Status: "enabled", VCPUs: 16, Memory: 32768, Disk: 500},
+			{UUID: "n3", Hostname: "compute-3", AvailabilityZone: "az2", State: "up", Status: "enabled", VCPUs: 16, Memory: 32768, Disk: 500},
+		},
+		Instances: []watcherclient.Instance{
+			{UUID: "vm1", NodeUUID: "n1", VCPUs: 8, Memory: 8192, Disk: 40},
+			{UUID: "vm2", NodeUUID: "n1", VCPUs: 8, Memory: 8192, Disk: 40},
+			{UUID: "vm3", NodeUUID: "n2", VCPUs: 4, Memory: 4096, Disk: 20},
+		},
+	}
+}
+
+// Test 1: Per-host utilization and overcommit
+func TestUtilization(t *testing.T) {
+	hosts := Utilization(testModel())
+
+	if len(hosts) != 3 {
+		t.Fatalf("Expected 3 hosts, got %d", len(hosts))
+	}
+
+	h := hosts[0]
+	if h.Hostname != "compute-1" || h.Instances != 2 {
+		t.Fatalf("Unexpected first host: %+v", h)
+	}
+
+	if h.VCPUsCapacity != 32 {
+		t.Errorf("Expected vCPU capacity 32, got %v", h.VCPUsCapacity)
+	}
+
+	if h.VCPUUtilization != 0.5 {
+		t.Errorf("Expected vCPU utilization 0.5, got %v", h.VCPUUtilization)
+	}
+
+	if h.VCPUOvercommit != 1 {
+		t.Errorf("Expected vCPU overcommit 1, got %v", h.VCPUOvercommit)
+	}
+}
+
+// Test 2: Empty and drainable hosts
+func TestEmptyAndDrainableHosts(t *testing.T) {
+	report := Analyze(testModel())
+
+	if len(report.EmptyHosts) != 1 || report.EmptyHosts[0] != "compute-3" {
+		t.Errorf("Expected compute-3 to be empty, got %v", report.EmptyHosts)
+	}
+
+	drainable := map[string]bool{}
+	for _, h := range report.DrainableHosts {
+		drainable[h] = true
+	}
+
+	if !drainable["compute-1"] || !drainable["compute-2"] {
+		t.Errorf("Expected compute-1 and compute-2 to be drainable, got %v", report.DrainableHosts)
+	}
+}
+
+// Test 3: Density per availability zone
+func TestDensityByZone(t *testing.T) {
+	density := DensityByZone(Utilization(testModel()))
+
+	if len(density) != 2 {
+		t.Fatalf("Expected 2 zones, got %d", len(density))
+	}
+
+	if density[0].Zone != "az1" || density[0].InstancesPerHost != 1.5 {
+		t.Errorf("Unexpected az1 density: %+v", density[0])
+	}
+}
+
+// Test 4: Fragmentation of free capacity
+func TestFragmentation(t *testing.T) {
+	hosts := []HostUtilization{
+		{VCPUsCapacity: 10, MemoryCapacity: 10, DiskCapacity: 10},
+	}
+
+	if f := Fragment(hosts); f.VCPU != 0 {
+		t.Errorf("Expected no fragmentation for a single host, got %v", f.VCPU)
+	}
+
+	hosts = append(hosts, HostUtilization{VCPUsCapacity: 10, MemoryCapacity: 10, DiskCapacity: 10})
+	if f := Fragment(hosts); f.VCPU != 0.5 {
+		t.Errorf("Expected fragmentation 0.5, got %v", f.VCPU)
+	}
+}
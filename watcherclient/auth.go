@@ -3,12 +3,15 @@ package watcherclient
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gophercloud/gophercloud/v2"
 	"github.com/gophercloud/gophercloud/v2/openstack"
-	"github.com/gophercloud/gophercloud/v2/openstack/identity/v3/tokens"
 )
 
 // AuthOptions contains authentication configuration
@@ -27,6 +30,29 @@ type AuthOptions struct {
 	AllowReauth                 bool
 	TokenID                     string
 	Scope                       *gophercloud.AuthScope
+	Region                      string               // Region of the Watcher endpoint
+	Interface                   string               // Catalog interface of the Watcher endpoint
+	ServiceName                 string               // Catalog name of the Watcher service
+	EndpointOverride            string               // Watcher endpoint used instead of the catalog
+	Transport                   http.RoundTripper    // Transport used for Keystone requests
+	Timeout                     time.Duration        // Timeout of Keystone requests, none when zero
+	TokenCache                  TokenCache           // Tokens shared with other processes
+	BackgroundRefresh           bool                 // Renew tokens in a goroutine, see Stop
+	RefreshLeadTime             time.Duration        // Remaining lifetime at which tokens are renewed
+	OnRefresh                   func(RefreshEvent)   // Called after each refresh, e.g. for metrics
+	OnWarning                   func(message string) // Called when a token is usable but incomplete
+
+	// Passcode is a TOTP code sent with the password. It is only valid for
+	// the initial authentication; PasscodeFunc provides one for each.
+	Passcode     string
+	PasscodeFunc func() (string, error)
+
+	// TokenProvider obtains a Keystone token, e.g. from a federated login,
+	// whenever one is needed. See CommandTokenProvider.
+	TokenProvider func() (string, error)
+
+	// Logger receives authentication and token refresh events
+	Logger *slog.Logger
 }
 
 // Authenticator handles authentication and token management
@@ -35,9 +61,19 @@ type Authenticator struct {
 	provider    *gophercloud.ProviderClient
 	token       string
 	tokenExpiry time.Time
+	identity    tokenIdentity
+	warning     string
 	endpoint    string
 	mutex       sync.RWMutex
 	autoReauth  bool
+	parent      *Authenticator // Source of the token rescoped by WithScope
+
+	flightMutex sync.Mutex
+	inflight    *refreshCall
+	stop        chan struct{}
+	stopped     chan struct{}
+	stopOnce    sync.Once
+	refreshing  atomic.Bool // Background refresher running
 }
 
 // NewAuthenticator creates a new authenticator instance
@@ -56,9 +92,17 @@ func NewAuthenticator(opts *AuthOptions) (*Authenticator, error) {
 		autoReauth:  opts.AllowReauth,
 	}
 
-	// Perform initial authentication
-	if err := auth.Authenticate(); err != nil {
-		return nil, fmt.Errorf("initial authentication failed: %w", err)
+	// Reuse a token cached by another process, or authenticate
+	if !auth.loadCachedToken() {
+		if err := auth.Authenticate(); err != nil {
+			return nil, fmt.Errorf("initial authentication failed: %w", err)
+		}
+		auth.log(slog.LevelDebug, "authenticated", slog.String("endpoint", auth.GetEndpoint()),
+			slog.Time("expires_at", auth.GetTokenExpiry()))
+	}
+
+	if opts.BackgroundRefresh && auth.canReauth() {
+		auth.startRefresher()
 	}
 
 	return auth, nil
@@ -66,9 +110,23 @@ func NewAuthenticator(opts *AuthOptions) (*Authenticator, error) {
 
 // Authenticate performs authentication against Keystone
 func (a *Authenticator) Authenticate() error {
-	a.mutex.Lock()
-	defer a.mutex.Unlock()
+	warning, err := a.authenticate()
+	if err != nil {
+		return err
+	}
+	if warning != "" {
+		a.log(slog.LevelWarn, warning)
+		if a.authOptions.OnWarning != nil {
+			a.authOptions.OnWarning(warning)
+		}
+	}
+	return nil
+}
 
+// authenticate obtains a new token and returns a warning about it, if any.
+// Keystone is called without holding the mutex, so that callers keep using
+// the current token until the new one is swapped in.
+func (a *Authenticator) authenticate() (string, error) {
 	// Build gophercloud auth options
 	authOpts := gophercloud.AuthOptions{
 		IdentityEndpoint:            a.authOptions.IdentityEndpoint,
@@ -87,58 +145,177 @@ func (a *Authenticator) Authenticate() error {
 		ApplicationCredentialSecret: a.authOptions.ApplicationCredentialSecret,
 	}
 
-	// Create authenticated client with context
+	if err := a.applyProviders(&authOpts); err != nil {
+		return "", err
+	}
+
+	// Rescoped tokens are renewed from the current token of the parent
+	if a.parent != nil {
+		token, err := a.parent.GetToken()
+		if err != nil {
+			return "", fmt.Errorf("failed to get token to rescope: %w", err)
+		}
+		authOpts.TokenID = token
+	}
+
+	// gophercloud only passes an unscoped token through and rejects
+	// AllowReauth for it; such tokens are renewed by refresh instead
+	if authOpts.TokenID != "" && (authOpts.Scope == nil || *authOpts.Scope == (gophercloud.AuthScope{})) {
+		authOpts.AllowReauth = false
+	}
+
+	provider, err := openstack.NewClient(a.authOptions.IdentityEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("failed to create provider client: %w", err)
+	}
+	// A hung Keystone would otherwise block every caller waiting on a refresh
+	provider.HTTPClient = http.Client{
+		Transport: a.authOptions.Transport,
+		Timeout:   a.authOptions.Timeout,
+	}
+
+	// Authenticate with context
 	ctx := context.Background()
-	provider, err := openstack.AuthenticatedClient(ctx, authOpts)
+	if err := openstack.Authenticate(ctx, provider, authOpts); err != nil {
+		return "", fmt.Errorf("failed to authenticate: %w", err)
+	}
+
+	// Expiry, roles and catalog come with the authentication response
+	identity, err := identityFromResult(provider.GetAuthResult())
+
+	var warning string
+	if err != nil || identity.ExpiresAt.IsZero() {
+		warning = "token expiry unknown, the token is only renewed after the Watcher API rejects it"
+		if err != nil {
+			warning += ": " + err.Error()
+		}
+	}
+
+	endpoint, err := a.locateEndpoint(provider)
 	if err != nil {
-		return fmt.Errorf("failed to authenticate: %w", err)
+		return "", err
 	}
 
+	a.mutex.Lock()
 	a.provider = provider
 	a.token = provider.TokenID
+	a.identity = identity
+	a.tokenExpiry = identity.ExpiresAt
+	a.warning = warning
+	a.endpoint = endpoint
+	a.mutex.Unlock()
 
-	// Get token expiry time if possible
-	if err := a.updateTokenExpiry(); err != nil {
-		// Non-fatal error, log but continue
-		// Token expiry will be checked on demand
+	a.storeCachedToken()
+
+	return warning, nil
+}
+
+// loadCachedToken uses the cached token if it is not close to expiry
+func (a *Authenticator) loadCachedToken() bool {
+	cache := a.tokenCache()
+	if cache == nil {
+		return false
 	}
 
-	// Get Watcher endpoint
-	endpointOpts := gophercloud.EndpointOpts{
-		Type: "infra-optim",
+	cached, err := cache.Load(tokenCacheKey(a.authOptions))
+	if err != nil || cached.Token == "" || cached.Endpoint == "" {
+		return false
+	}
+	if time.Until(cached.ExpiresAt) < a.refreshLead() {
+		return false
 	}
 
-	endpoint, err := provider.EndpointLocator(endpointOpts)
-	if err != nil {
-		return fmt.Errorf("failed to locate Watcher endpoint: %w", err)
+	a.log(slog.LevelDebug, "using cached token", slog.Time("expires_at", cached.ExpiresAt))
+	a.token = cached.Token
+	a.tokenExpiry = cached.ExpiresAt
+	a.endpoint = cached.Endpoint
+	a.identity = tokenIdentity{
+		ExpiresAt: cached.ExpiresAt,
+		IssuedAt:  cached.IssuedAt,
+		UserID:    cached.UserID,
+		ProjectID: cached.ProjectID,
+		Roles:     cached.Roles,
 	}
+	return true
+}
 
-	a.endpoint = endpoint
+// storeCachedToken saves the current token for other processes. Tokens of
+// unknown expiry are not cached.
+func (a *Authenticator) storeCachedToken() {
+	cache := a.tokenCache()
+	if cache == nil {
+		return
+	}
 
-	return nil
+	a.mutex.RLock()
+	cached := CachedToken{
+		Token:     a.token,
+		ExpiresAt: a.tokenExpiry,
+		IssuedAt:  a.identity.IssuedAt,
+		Endpoint:  a.endpoint,
+		UserID:    a.identity.UserID,
+		ProjectID: a.identity.ProjectID,
+		Roles:     a.identity.Roles,
+	}
+	a.mutex.RUnlock()
+	if cached.ExpiresAt.IsZero() {
+		return
+	}
+
+	// Non-fatal, the next process authenticates again
+	_ = cache.Store(tokenCacheKey(a.authOptions), cached)
 }
 
-// updateTokenExpiry extracts and updates token expiry time
-func (a *Authenticator) updateTokenExpiry() error {
-	if a.provider == nil {
-		return fmt.Errorf("provider not initialized")
+// tokenCache returns the cache shared with other processes, nil when the
+// cache key cannot identify what the tokens are bound to: a token provider,
+// or a passcode which a cached token would bypass
+func (a *Authenticator) tokenCache() TokenCache {
+	opts := a.authOptions
+	if opts.TokenProvider != nil || opts.Passcode != "" || opts.PasscodeFunc != nil {
+		return nil
 	}
+	return opts.TokenCache
+}
 
-	// Create identity v3 client
-	identityClient, err := openstack.NewIdentityV3(a.provider, gophercloud.EndpointOpts{})
-	if err != nil {
-		return fmt.Errorf("failed to create identity client: %w", err)
+// locateEndpoint returns the Watcher endpoint, without API version, from
+// the override or the service catalog
+func (a *Authenticator) locateEndpoint(provider *gophercloud.ProviderClient) (string, error) {
+	if a.authOptions.EndpointOverride != "" {
+		endpoint := strings.TrimSuffix(a.authOptions.EndpointOverride, "/")
+		return strings.TrimSuffix(endpoint, "/"+DefaultAPIVersion), nil
 	}
 
-	// Get token details with context
-	ctx := context.Background()
-	tokenDetails, err := tokens.Get(ctx, identityClient, a.token).Extract()
+	endpointOpts := gophercloud.EndpointOpts{
+		Type:         "infra-optim",
+		Name:         a.authOptions.ServiceName,
+		Region:       a.authOptions.Region,
+		Availability: availability(a.authOptions.Interface),
+	}
+	// The catalog lookup rejects an empty availability
+	endpointOpts.ApplyDefaults("infra-optim")
+
+	endpoint, err := provider.EndpointLocator(endpointOpts)
 	if err != nil {
-		return fmt.Errorf("failed to get token details: %w", err)
+		return "", fmt.Errorf("failed to locate Watcher endpoint (region %q, interface %q, name %q): %w",
+			endpointOpts.Region, endpointOpts.Availability, endpointOpts.Name, err)
 	}
 
-	a.tokenExpiry = tokenDetails.ExpiresAt
-	return nil
+	return strings.TrimSuffix(endpoint, "/"), nil
+}
+
+// availability converts an interface name, including the "publicURL" style
+// of older clients, to a catalog availability
+func availability(iface string) gophercloud.Availability {
+	switch strings.TrimSuffix(strings.ToLower(iface), "url") {
+	case "internal":
+		return gophercloud.AvailabilityInternal
+	case "admin":
+		return gophercloud.AvailabilityAdmin
+	case "public":
+		return gophercloud.AvailabilityPublic
+	default:
+		return ""
+	}
 }
 
 // GetToken returns the current valid token
@@ -148,11 +325,16 @@ func (a *Authenticator) GetToken() (string, error) {
 	expiry := a.tokenExpiry
 	a.mutex.RUnlock()
 
-	// Check if token is expired or about to expire (5 min buffer)
-	if !expiry.IsZero() && time.Until(expiry) < 5*time.Minute {
-		if a.autoReauth {
+	// Check if token is expired or about to expire. The background
+	// refresher, when running, renews it before it expires.
+	lead := a.refreshLead()
+	if a.refreshing.Load() {
+		lead = 0
+	}
+	if !expiry.IsZero() && time.Until(expiry) < lead {
+		if a.canReauth() {
 			// Token expired or expiring soon, re-authenticate
-			if err := a.Authenticate(); err != nil {
+			if err := a.refresh(false); err != nil {
 				return "", fmt.Errorf("failed to re-authenticate: %w", err)
 			}
 			// Get new token
@@ -174,7 +356,8 @@ func (a *Authenticator) GetEndpoint() string {
 	return a.endpoint
 }
 
-// GetProvider returns the gophercloud provider client
+// GetProvider returns the gophercloud provider client, nil while a cached
+// token is used
 func (a *Authenticator) GetProvider() *gophercloud.ProviderClient {
 	a.mutex.RLock()
 	defer a.mutex.RUnlock()
@@ -200,9 +383,10 @@ func (a *Authenticator) GetTokenExpiry() time.Time {
 	return a.tokenExpiry
 }
 
-// Reauth forces re-authentication
+// Reauth forces re-authentication. Concurrent calls share a single
+// request to Keystone.
 func (a *Authenticator) Reauth() error {
-	return a.Authenticate()
+	return a.refresh(false)
 }
 
 // TokenAuthenticator creates an authenticator with existing token
@@ -232,6 +416,41 @@ func (t *TokenAuthenticator) GetEndpoint() string {
 	return t.endpoint
 }
 
+// NoAuthAuthenticator is used with Watcher deployments running with
+// auth_strategy = noauth, such as devstack or local test servers. No token
+// is sent.
+type NoAuthAuthenticator struct {
+	endpoint  string
+	projectID string
+}
+
+// NewNoAuthAuthenticator creates a noauth authenticator. A non-empty
+// projectID is sent as X-Project-Id.
+func NewNoAuthAuthenticator(endpoint, projectID string) *NoAuthAuthenticator {
+	return &NoAuthAuthenticator{
+		endpoint:  endpoint,
+		projectID: projectID,
+	}
+}
+
+// GetToken returns an empty token, omitting the X-Auth-Token header
+func (n *NoAuthAuthenticator) GetToken() (string, error) {
+	return "", nil
+}
+
+// GetEndpoint returns the endpoint
+func (n *NoAuthAuthenticator) GetEndpoint() string {
+	return n.endpoint
+}
+
+// AuthHeaders returns the headers identifying the caller
+func (n *NoAuthAuthenticator) AuthHeaders() map[string]string {
+	if n.projectID == "" {
+		return nil
+	}
+	return map[string]string{"X-Project-Id": n.projectID}
+}
+
 // ValidateAuthOptions validates authentication options
 func ValidateAuthOptions(opts *AuthOptions) error {
 	if opts == nil {
@@ -243,8 +462,9 @@ func ValidateAuthOptions(opts *AuthOptions) error {
 	}
 
 	// Check if we have valid authentication method
-	hasPassword := opts.Username != "" && opts.Password != ""
-	hasToken := opts.TokenID != ""
+	hasPassword := (opts.Username != "" || opts.UserID != "") &&
+		(opts.Password != "" || opts.Passcode != "" || opts.PasscodeFunc != nil)
+	hasToken := opts.TokenID != "" || opts.TokenProvider != nil
 	hasAppCred := opts.ApplicationCredentialID != "" && opts.ApplicationCredentialSecret != ""
 	hasAppCredName := opts.ApplicationCredentialName != "" && opts.ApplicationCredentialSecret != ""
 
@@ -254,10 +474,21 @@ func ValidateAuthOptions(opts *AuthOptions) error {
 
 	// Validate scope
 	if opts.Scope != nil {
-		if opts.Scope.ProjectID == "" && opts.Scope.ProjectName == "" &&
-			opts.Scope.DomainID == "" && opts.Scope.DomainName == "" {
+		hasProject := opts.Scope.ProjectID != "" || opts.Scope.ProjectName != ""
+		hasDomain := opts.Scope.DomainID != "" || opts.Scope.DomainName != ""
+
+		if opts.Scope.System && (hasProject || hasDomain) {
+			return fmt.Errorf("system scope cannot be combined with a project or domain")
+		}
+		if !opts.Scope.System && !hasProject && !hasDomain && opts.Scope.TrustID == "" {
 			return fmt.Errorf("scope must specify either project or domain")
 		}
+		if hasAppCred || hasAppCredName {
+			return fmt.Errorf("application credentials cannot be combined with a scope")
+		}
+		if opts.Scope.ProjectName != "" && !hasDomain {
+			return fmt.Errorf("project name requires a project domain ID or name")
+		}
 	}
 
 	return nil
@@ -266,24 +497,78 @@ func ValidateAuthOptions(opts *AuthOptions) error {
 // BuildAuthOptions builds AuthOptions from ClientOptions
 func BuildAuthOptions(opts ClientOptions) *AuthOptions {
 	authOpts := &AuthOptions{
-		IdentityEndpoint: opts.AuthURL,
-		Username:         opts.Username,
-		Password:         opts.Password,
-		DomainID:         opts.UserDomainID,
-		AllowReauth:      true,
+		IdentityEndpoint:            opts.AuthURL,
+		Username:                    opts.Username,
+		UserID:                      opts.UserID,
+		Password:                    opts.Password,
+		DomainID:                    opts.UserDomainID,
+		DomainName:                  opts.UserDomainName,
+		ApplicationCredentialID:     opts.ApplicationCredentialID,
+		ApplicationCredentialName:   opts.ApplicationCredentialName,
+		ApplicationCredentialSecret: opts.ApplicationCredentialSecret,
+		TokenID:                     opts.Token,
+		Region:                      opts.Region,
+		Interface:                   opts.Interface,
+		ServiceName:                 opts.ServiceName,
+		EndpointOverride:            opts.EndpointOverride,
+		Timeout:                     opts.Timeout,
+		TokenCache:                  opts.TokenCache,
+		BackgroundRefresh:           opts.BackgroundRefresh,
+		RefreshLeadTime:             opts.RefreshLeadTime,
+		OnRefresh:                   opts.OnRefresh,
+		OnWarning:                   opts.OnWarning,
+		Passcode:                    opts.Passcode,
+		TokenProvider:               opts.TokenProvider,
+		Logger:                      opts.Logger,
 	}
 
-	// Build scope
-	if opts.ProjectName != "" || opts.ProjectDomainID != "" {
+	// Build scope. Application credentials carry their own scope; otherwise
+	// system scope wins over project scope, which wins over domain scope.
+	hasAppCred := opts.ApplicationCredentialID != "" || opts.ApplicationCredentialName != ""
+	switch {
+	case hasAppCred:
+	case opts.SystemScope != "":
+		authOpts.Scope = &gophercloud.AuthScope{System: true}
+	case opts.ProjectID != "" || opts.ProjectName != "":
 		authOpts.Scope = &gophercloud.AuthScope{
+			ProjectID:   opts.ProjectID,
 			ProjectName: opts.ProjectName,
 			DomainID:    opts.ProjectDomainID,
+			DomainName:  opts.ProjectDomainName,
+		}
+	case opts.DomainID != "" || opts.DomainName != "":
+		authOpts.Scope = &gophercloud.AuthScope{
+			DomainID:   opts.DomainID,
+			DomainName: opts.DomainName,
 		}
 	}
 
+	authOpts.AllowReauth = reusableCredentials(opts, authOpts.Scope)
 	return authOpts
 }
 
+// validateScopeOptions checks the scope settings of opts which
+// BuildAuthOptions cannot carry over, such as a project domain without a
+// project. Application credentials ignore them, as they carry their own scope.
+func validateScopeOptions(opts ClientOptions) error {
+	if opts.ApplicationCredentialID != "" || opts.ApplicationCredentialName != "" {
+		return nil
+	}
+	hasProjectDomain := opts.ProjectDomainID != "" || opts.ProjectDomainName != ""
+	if hasProjectDomain && opts.ProjectID == "" && opts.ProjectName == "" {
+		return fmt.Errorf("project domain requires a project ID or name")
+	}
+	return nil
+}
+
+// reusableCredentials reports whether opts can obtain a new token once the
+// current one expires. gophercloud refuses to re-authenticate with a token
+// it can only pass through, as without a scope.
+func reusableCredentials(opts ClientOptions, scope *gophercloud.AuthScope) bool {
+	return opts.Password != "" || opts.ApplicationCredentialSecret != "" ||
+		opts.TokenProvider != nil || (opts.Token != "" && scope != nil)
+}
+
 // AuthInfo contains authentication information for debugging
 type AuthInfo struct {
 	Username        string
@@ -292,12 +577,17 @@ type AuthInfo struct {
 	ProjectID       string
 	DomainName      string
 	DomainID        string
-	TokenExpiry     time.Time
+	Roles           []string
+	Catalog         []CatalogEntry // Empty while a cached token is used
+	IssuedAt        time.Time
+	TokenExpiry     time.Time // Zero when unknown, see Warning
 	IsExpired       bool
 	TimeUntilExpiry time.Duration
+	Warning         string // Set when the token response was incomplete
 }
 
-// GetAuthInfo returns current authentication information
+// GetAuthInfo returns current authentication information. Values reported
+// by Keystone for the token take precedence over the configured ones.
 func (a *Authenticator) GetAuthInfo() AuthInfo {
 	a.mutex.RLock()
 	defer a.mutex.RUnlock()
@@ -307,7 +597,11 @@ func (a *Authenticator) GetAuthInfo() AuthInfo {
 		UserID:      a.authOptions.UserID,
 		DomainName:  a.authOptions.DomainName,
 		DomainID:    a.authOptions.DomainID,
+		Roles:       a.identity.Roles,
+		Catalog:     a.identity.Catalog,
+		IssuedAt:    a.identity.IssuedAt,
 		TokenExpiry: a.tokenExpiry,
+		Warning:     a.warning,
 	}
 
 	if a.authOptions.Scope != nil {
@@ -315,77 +609,27 @@ func (a *Authenticator) GetAuthInfo() AuthInfo {
 		info.ProjectID = a.authOptions.Scope.ProjectID
 	}
 
-	if !a.tokenExpiry.IsZero() {
-		info.IsExpired = time.Now().After(a.tokenExpiry)
-		info.TimeUntilExpiry = time.Until(a.tokenExpiry)
+	identity := a.identity
+	if identity.UserID != "" {
+		info.UserID = identity.UserID
 	}
-
-	return info
-}
-
-// SessionManager manages multiple authentication sessions
-type SessionManager struct {
-	sessions map[string]*Authenticator
-	mutex    sync.RWMutex
-}
-
-// NewSessionManager creates a new session manager
-func NewSessionManager() *SessionManager {
-	return &SessionManager{
-		sessions: make(map[string]*Authenticator),
+	if identity.Username != "" {
+		info.Username = identity.Username
 	}
-}
-
-// AddSession adds a new authentication session
-func (sm *SessionManager) AddSession(name string, auth *Authenticator) {
-	sm.mutex.Lock()
-	defer sm.mutex.Unlock()
-	sm.sessions[name] = auth
-}
-
-// GetSession retrieves an authentication session
-func (sm *SessionManager) GetSession(name string) (*Authenticator, error) {
-	sm.mutex.RLock()
-	defer sm.mutex.RUnlock()
-
-	auth, exists := sm.sessions[name]
-	if !exists {
-		return nil, fmt.Errorf("session '%s' not found", name)
+	if identity.UserDomainID != "" {
+		info.DomainID, info.DomainName = identity.UserDomainID, identity.UserDomainName
 	}
-
-	return auth, nil
-}
-
-// RemoveSession removes an authentication session
-func (sm *SessionManager) RemoveSession(name string) {
-	sm.mutex.Lock()
-	defer sm.mutex.Unlock()
-	delete(sm.sessions, name)
-}
-
-// ListSessions returns all session names
-func (sm *SessionManager) ListSessions() []string {
-	sm.mutex.RLock()
-	defer sm.mutex.RUnlock()
-
-	names := make([]string, 0, len(sm.sessions))
-	for name := range sm.sessions {
-		names = append(names, name)
+	if identity.ProjectID != "" {
+		info.ProjectID = identity.ProjectID
+	}
+	if identity.ProjectName != "" {
+		info.ProjectName = identity.ProjectName
 	}
-	return names
-}
-
-// CleanupExpiredSessions removes expired sessions
-func (sm *SessionManager) CleanupExpiredSessions() int {
-	sm.mutex.Lock()
-	defer sm.mutex.Unlock()
 
-	removed := 0
-	for name, auth := range sm.sessions {
-		if auth.IsTokenExpired() {
-			delete(sm.sessions, name)
-			removed++
-		}
+	if !a.tokenExpiry.IsZero() {
+		info.IsExpired = time.Now().After(a.tokenExpiry)
+		info.TimeUntilExpiry = time.Until(a.tokenExpiry)
 	}
-	return removed
+
+	return info
 }
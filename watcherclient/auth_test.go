@@ -0,0 +1,103 @@
+package watcherclient_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+	"github.com/overwatch144/golang-watcherclient/watcherclient/watchertest"
+)
+
+// Test 1: Keystone requests are bounded by the client timeout
+func TestKeystoneTimeout(t *testing.T) {
+	server := watchertest.NewServer(watchertest.Options{})
+	defer server.Close()
+	server.SetKeystoneLatency(time.Second)
+
+	opts := server.ClientOptions()
+	opts.Timeout = 50 * time.Millisecond
+
+	start := time.Now()
+	if _, err := watcherclient.NewClient(opts); err == nil {
+		t.Fatal("Expected authentication to time out")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected authentication to give up after the timeout, took %v", elapsed)
+	}
+}
+
+// Test 2: Authentication with an existing token
+func TestTokenAuth(t *testing.T) {
+	server := watchertest.NewServer(watchertest.Options{})
+	defer server.Close()
+
+	client, err := watcherclient.NewClient(watcherclient.ClientOptions{
+		AuthURL:         server.AuthURL(),
+		Token:           server.Token(),
+		ProjectName:     "admin",
+		ProjectDomainID: "default",
+	})
+	if err != nil {
+		t.Fatalf("NewClient with token failed: %v", err)
+	}
+
+	if _, err := client.ListGoals(nil); err != nil {
+		t.Errorf("ListGoals failed: %v", err)
+	}
+}
+
+// Test 3: Endpoint selection by region, interface, name and override
+func TestEndpointSelection(t *testing.T) {
+	server := watchertest.NewServer(watchertest.Options{})
+	defer server.Close()
+
+	internal := server.ClientOptions()
+	internal.Interface = "internalURL"
+	if _, err := watcherclient.NewClient(internal); err != nil {
+		t.Errorf("Expected internal endpoint, got %v", err)
+	}
+
+	wrongRegion := server.ClientOptions()
+	wrongRegion.Region = "RegionTwo"
+	if _, err := watcherclient.NewClient(wrongRegion); err == nil {
+		t.Error("Expected error for unknown region")
+	}
+
+	wrongName := server.ClientOptions()
+	wrongName.ServiceName = "watcher-legacy"
+	if _, err := watcherclient.NewClient(wrongName); err == nil {
+		t.Error("Expected error for unknown service name")
+	}
+
+	override := wrongRegion
+	override.EndpointOverride = server.Endpoint() + "/v1/"
+	client, err := watcherclient.NewClient(override)
+	if err != nil {
+		t.Fatalf("Expected endpoint override to skip the catalog, got %v", err)
+	}
+	if client.GetEndpoint() != server.Endpoint()+"/v1" {
+		t.Errorf("Expected endpoint %s/v1, got %s", server.Endpoint(), client.GetEndpoint())
+	}
+	if _, err := client.ListGoals(nil); err != nil {
+		t.Errorf("ListGoals through override failed: %v", err)
+	}
+}
+
+// Test 4: A token without scope from the environment is passed through
+func TestTokenOnlyFromEnv(t *testing.T) {
+	server := watchertest.NewServer(watchertest.Options{})
+	defer server.Close()
+
+	t.Setenv("OS_AUTH_URL", server.AuthURL())
+	t.Setenv("OS_TOKEN", server.Token())
+
+	client, err := watcherclient.NewClientFromEnv()
+	if err != nil {
+		t.Fatalf("NewClientFromEnv with a token failed: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.ListGoals(nil); err != nil {
+		t.Errorf("ListGoals failed: %v", err)
+	}
+}
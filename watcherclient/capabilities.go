@@ -0,0 +1,120 @@
+package watcherclient
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Roles granting the Watcher admin_api rule, and read access under the
+// reader defaults of recent Watcher releases
+var (
+	AdminRoles  = []string{"admin", "administrator"}
+	ReaderRoles = []string{"reader", "member", "_member_"}
+)
+
+// Sources of a capability report
+const (
+	CapabilitySourceRoles = "roles"
+	CapabilitySourceProbe = "probe"
+)
+
+// Capabilities reports the Watcher operations allowed to the current
+// credentials. It is a best guess from the default policy: deployments with
+// a custom policy.yaml can differ, and IsForbidden remains authoritative.
+type Capabilities struct {
+	Roles  []string
+	Source string // CapabilitySourceRoles or CapabilitySourceProbe
+
+	ReadResources        bool // List and show audits, action plans, goals, ...
+	CreateAudits         bool
+	ManageAuditTemplates bool
+	TriggerActionPlans   bool // Start and cancel action plans
+	DeleteResources      bool
+	ReadDataModel        bool
+}
+
+// CapabilitiesFromRoles applies the default Watcher policy to roles
+func CapabilitiesFromRoles(roles []string) Capabilities {
+	admin := hasRole(roles, AdminRoles)
+	reader := admin || hasRole(roles, ReaderRoles)
+
+	return Capabilities{
+		Roles:                roles,
+		Source:               CapabilitySourceRoles,
+		ReadResources:        reader,
+		CreateAudits:         admin,
+		ManageAuditTemplates: admin,
+		TriggerActionPlans:   admin,
+		DeleteResources:      admin,
+		ReadDataModel:        admin,
+	}
+}
+
+// Capabilities returns the operations allowed by the roles of the token.
+// It fails for clients created from a bare token, whose roles are unknown.
+func (c *Client) Capabilities() (*Capabilities, error) {
+	info, err := c.GetAuthInfo()
+	if err != nil {
+		return nil, fmt.Errorf("roles unknown: %w", err)
+	}
+	if len(info.Roles) == 0 {
+		return nil, fmt.Errorf("roles unknown: the token did not report any role")
+	}
+
+	caps := CapabilitiesFromRoles(info.Roles)
+	return &caps, nil
+}
+
+// ProbeCapabilities refines the role-based report with read-only requests
+// to Watcher, which also works for bare tokens. Write operations are not
+// probed; they are assumed allowed only with an admin role.
+func (c *Client) ProbeCapabilities() (*Capabilities, error) {
+	caps, err := c.Capabilities()
+	if err != nil {
+		caps = &Capabilities{}
+	}
+	caps.Source = CapabilitySourceProbe
+
+	if caps.ReadResources, err = c.probe("/audits?limit=1"); err != nil {
+		return nil, err
+	}
+	if caps.ReadDataModel, err = c.probe("/data_model?type=" + DataModelTypeCompute); err != nil {
+		return nil, err
+	}
+
+	if !caps.ReadResources {
+		caps.CreateAudits = false
+		caps.ManageAuditTemplates = false
+		caps.TriggerActionPlans = false
+		caps.DeleteResources = false
+	}
+
+	return caps, nil
+}
+
+// probe reports whether a GET request is allowed. Errors other than 403
+// are returned.
+func (c *Client) probe(path string) (bool, error) {
+	resp, err := c.doRequest(http.MethodGet, path, nil)
+	if IsForbidden(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("capability probe failed: %w", err)
+	}
+	resp.Body.Close()
+	return true, nil
+}
+
+// hasRole reports whether roles contain one of wanted, ignoring case
+func hasRole(roles, wanted []string) bool {
+	for _, role := range roles {
+		for _, w := range wanted {
+			if strings.EqualFold(role, w) {
+				return true
+			}
+		}
+	}
+	return false
+}
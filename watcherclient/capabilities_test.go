@@ -0,0 +1,57 @@
+package watcherclient_test
+
+import (
+	"testing"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+	"github.com/overwatch144/golang-watcherclient/watcherclient/watchertest"
+)
+
+// Test 1: Capabilities from roles and probes
+func TestCapabilities(t *testing.T) {
+	admin := watchertest.NewServer(watchertest.Options{})
+	defer admin.Close()
+
+	client, err := watcherclient.NewClient(admin.ClientOptions())
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	caps, err := client.Capabilities()
+	if err != nil {
+		t.Fatalf("Capabilities failed: %v", err)
+	}
+	if !caps.CreateAudits || !caps.TriggerActionPlans || !caps.ReadDataModel {
+		t.Errorf("Expected full capabilities for admin, got %+v", caps)
+	}
+
+	reader := watchertest.NewServer(watchertest.Options{Roles: []string{"reader"}})
+	defer reader.Close()
+
+	client, err = watcherclient.NewClient(reader.ClientOptions())
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	caps, err = client.Capabilities()
+	if err != nil {
+		t.Fatalf("Capabilities failed: %v", err)
+	}
+	if !caps.ReadResources || caps.CreateAudits || caps.ReadDataModel {
+		t.Errorf("Expected read-only capabilities for reader, got %+v", caps)
+	}
+	if _, err := client.CreateAudit(&watcherclient.Audit{AuditType: "ONESHOT", Goal: "dummy"}); !watcherclient.IsForbidden(err) {
+		t.Errorf("Expected 403 creating an audit as reader, got %v", err)
+	}
+
+	// Bare tokens have no roles, probing still finds read access
+	bare := reader.Client()
+	if _, err := bare.Capabilities(); err == nil {
+		t.Error("Expected error without roles")
+	}
+	caps, err = bare.ProbeCapabilities()
+	if err != nil {
+		t.Fatalf("ProbeCapabilities failed: %v", err)
+	}
+	if caps.Source != watcherclient.CapabilitySourceProbe || !caps.ReadResources || caps.ReadDataModel || caps.CreateAudits {
+		t.Errorf("Expected probed read access only, got %+v", caps)
+	}
+}
@@ -0,0 +1,380 @@
+// Package cassette records the HTTP traffic of a Watcher client to a file
+// and replays it later, so that behavior captured against a real cloud can
+// be used in offline regression tests.
+//
+// Example use:
+//
+//	rec, err := cassette.New("testdata/audits.json", cassette.Options{Mode: cassette.ModeRecord})
+//	rec.Install(client)
+//	... exercise the client ...
+//	err = rec.Stop() // writes the cassette
+//
+// In ModeReplay the same code runs without network access.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Mode selects whether a Recorder records or replays traffic
+type Mode int
+
+const (
+	ModeRecord Mode = iota // Forward requests and capture them
+	ModeReplay             // Serve captured responses only
+)
+
+// Version is the cassette file format version
+const Version = 1
+
+// Redacted replaces the value of redacted headers
+const Redacted = "REDACTED"
+
+// DefaultRedactHeaders are redacted from recorded requests and responses
+var DefaultRedactHeaders = []string{"X-Auth-Token", "X-Subject-Token", "Authorization", "Cookie", "Set-Cookie"}
+
+// tokenHeaders are redacted from saved cassettes whatever RedactHeaders says
+var tokenHeaders = []string{"X-Auth-Token", "X-Subject-Token"}
+
+// ErrNoMatch is returned in replay mode when no recorded interaction
+// matches a request
+var ErrNoMatch = errors.New("no matching interaction in cassette")
+
+// Request is a recorded HTTP request
+type Request struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Path    string      `json:"path"`
+	Query   string      `json:"query,omitempty"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+// Response is a recorded HTTP response
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Interaction is a request and the response it received
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Cassette is the content of a cassette file
+type Cassette struct {
+	Version      int           `json:"version"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// MatchOptions selects the request attributes compared during replay
+type MatchOptions struct {
+	Method bool
+	Path   bool
+	Query  bool
+	Body   bool // JSON bodies are compared structurally
+}
+
+// DefaultMatchOptions compares every attribute
+var DefaultMatchOptions = MatchOptions{Method: true, Path: true, Query: true, Body: true}
+
+// Options configures a Recorder
+type Options struct {
+	Mode          Mode
+	Match         *MatchOptions     // Defaults to DefaultMatchOptions
+	Transport     http.RoundTripper // Used in record mode, defaults to the transport replaced by Install
+	RedactHeaders []string          // Defaults to DefaultRedactHeaders
+}
+
+// Transporter is a client whose transport can be replaced, such as
+// *watcherclient.Client
+type Transporter interface {
+	GetTransport() http.RoundTripper
+	SetTransport(rt http.RoundTripper)
+}
+
+// Recorder is an http.RoundTripper recording or replaying a cassette
+type Recorder struct {
+	path     string
+	opts     Options
+	mutex    sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// New creates a Recorder for the cassette at path. In replay mode the
+// cassette is loaded immediately.
+func New(path string, opts Options) (*Recorder, error) {
+	if opts.Match == nil {
+		match := DefaultMatchOptions
+		opts.Match = &match
+	}
+	if opts.RedactHeaders == nil {
+		opts.RedactHeaders = DefaultRedactHeaders
+	}
+
+	r := &Recorder{
+		path:     path,
+		opts:     opts,
+		cassette: &Cassette{Version: Version},
+	}
+
+	if opts.Mode == ModeReplay {
+		cassette, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		r.cassette = cassette
+		r.used = make([]bool, len(cassette.Interactions))
+	}
+
+	return r, nil
+}
+
+// Load reads a cassette file
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette: %w", err)
+	}
+	if cassette.Version != Version {
+		return nil, fmt.Errorf("unsupported cassette version %d", cassette.Version)
+	}
+
+	return &cassette, nil
+}
+
+// Save writes a cassette file only readable by the current user, creating
+// its directory if needed. Token headers are always redacted.
+func (c *Cassette) Save(path string) error {
+	saved := Cassette{Version: c.Version, Interactions: make([]Interaction, len(c.Interactions))}
+	for i, interaction := range c.Interactions {
+		interaction.Request.Headers = redactHeaders(interaction.Request.Headers, tokenHeaders)
+		interaction.Response.Headers = redactHeaders(interaction.Response.Headers, tokenHeaders)
+		saved.Interactions[i] = interaction
+	}
+
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+
+	return nil
+}
+
+// Install makes client send its requests through the recorder. In record
+// mode they are forwarded to the transport of the client, keeping its TLS
+// and proxy settings, unless Options.Transport is set.
+func (r *Recorder) Install(client Transporter) {
+	r.mutex.Lock()
+	if r.opts.Transport == nil {
+		r.opts.Transport = client.GetTransport()
+	}
+	r.mutex.Unlock()
+	client.SetTransport(r)
+}
+
+// Interactions returns the interactions recorded or loaded so far
+func (r *Recorder) Interactions() []Interaction {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]Interaction(nil), r.cassette.Interactions...)
+}
+
+// Remaining returns the number of replayable interactions not yet served
+func (r *Recorder) Remaining() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	remaining := 0
+	for _, used := range r.used {
+		if !used {
+			remaining++
+		}
+	}
+	return remaining
+}
+
+// Stop writes the cassette in record mode. It does nothing in replay mode.
+func (r *Recorder) Stop() error {
+	if r.opts.Mode != ModeRecord {
+		return nil
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.cassette.Save(r.path)
+}
+
+// RoundTrip implements http.RoundTripper. The request is cloned, as its
+// body is read for matching and recording.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	if req.Body != nil {
+		req.Body.Close()
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if r.opts.Mode == ModeReplay {
+		return r.replay(req, body)
+	}
+	return r.record(req, body)
+}
+
+// record forwards a request and captures the exchange
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	r.mutex.Lock()
+	transport := r.opts.Transport
+	r.mutex.Unlock()
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := readBody(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	interaction := Interaction{
+		Request: Request{
+			Method:  req.Method,
+			URL:     req.URL.String(),
+			Path:    req.URL.Path,
+			Query:   req.URL.RawQuery,
+			Headers: r.redact(req.Header),
+			Body:    string(body),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Headers:    r.redact(resp.Header),
+			Body:       string(respBody),
+		},
+	}
+
+	r.mutex.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mutex.Unlock()
+
+	return resp, nil
+}
+
+// replay serves the first unused interaction matching the request
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || !r.matches(interaction.Request, req, body) {
+			continue
+		}
+		r.used[i] = true
+
+		recorded := interaction.Response
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+			StatusCode:    recorded.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        recorded.Headers.Clone(),
+			Body:          io.NopCloser(strings.NewReader(recorded.Body)),
+			ContentLength: int64(len(recorded.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrNoMatch, req.Method, req.URL.RequestURI())
+}
+
+// matches compares a recorded request with a live one
+func (r *Recorder) matches(recorded Request, req *http.Request, body []byte) bool {
+	m := r.opts.Match
+	if m.Method && recorded.Method != req.Method {
+		return false
+	}
+	if m.Path && recorded.Path != req.URL.Path {
+		return false
+	}
+	if m.Query && recorded.Query != req.URL.RawQuery {
+		return false
+	}
+	if m.Body && !sameBody([]byte(recorded.Body), body) {
+		return false
+	}
+	return true
+}
+
+// redact returns a copy of headers with sensitive values replaced
+func (r *Recorder) redact(headers http.Header) http.Header {
+	return redactHeaders(headers, r.opts.RedactHeaders)
+}
+
+// redactHeaders returns a copy of headers with the values of names replaced
+func redactHeaders(headers http.Header, names []string) http.Header {
+	redacted := headers.Clone()
+	for _, name := range names {
+		if values := redacted.Values(name); len(values) > 0 {
+			redacted.Del(name)
+			for range values {
+				redacted.Add(name, Redacted)
+			}
+		}
+	}
+	return redacted
+}
+
+// sameBody compares two bodies, structurally when both are JSON
+func sameBody(a, b []byte) bool {
+	if bytes.Equal(a, b) {
+		return true
+	}
+
+	var va, vb interface{}
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// readBody reads a possibly nil body
+func readBody(body io.ReadCloser) ([]byte, error) {
+	if body == nil {
+		return nil, nil
+	}
+	return io.ReadAll(body)
+}
@@ -0,0 +1,182 @@
+package cassette
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+	"github.com/overwatch144/golang-watcherclient/watcherclient/watchertest"
+)
+
+// Test 1: Record against the fake server and replay offline
+func TestRecordReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassettes", "goals.json")
+
+	server := watchertest.NewServer(watchertest.Options{})
+	token := server.Token()
+	client := watcherclient.NewClientWithToken(server.Endpoint(), token)
+
+	rec, err := New(path, Options{Mode: ModeRecord})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	rec.Install(client)
+
+	recorded, err := client.ListGoals(&watcherclient.ListOptions{Limit: 3})
+	if err != nil {
+		t.Fatalf("ListGoals failed: %v", err)
+	}
+	audit, err := client.CreateAudit(&watcherclient.Audit{AuditType: "ONESHOT", Goal: "dummy"})
+	if err != nil {
+		t.Fatalf("CreateAudit failed: %v", err)
+	}
+	if err := rec.Stop(); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	server.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if strings.Contains(string(data), token) {
+		t.Error("Expected token to be redacted from the cassette")
+	}
+
+	replay, err := New(path, Options{Mode: ModeReplay})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	offline := watcherclient.NewClientWithToken(server.Endpoint(), "other-token")
+	replay.Install(offline)
+
+	goals, err := offline.ListGoals(&watcherclient.ListOptions{Limit: 3})
+	if err != nil {
+		t.Fatalf("Replayed ListGoals failed: %v", err)
+	}
+	if len(goals) != len(recorded) || goals[0].UUID != recorded[0].UUID {
+		t.Errorf("Expected replayed goals %+v, got %+v", recorded, goals)
+	}
+
+	replayed, err := offline.CreateAudit(&watcherclient.Audit{AuditType: "ONESHOT", Goal: "dummy"})
+	if err != nil {
+		t.Fatalf("Replayed CreateAudit failed: %v", err)
+	}
+	if replayed.UUID != audit.UUID {
+		t.Errorf("Expected audit %s, got %s", audit.UUID, replayed.UUID)
+	}
+
+	if replay.Remaining() != 0 {
+		t.Errorf("Expected no remaining interactions, got %d", replay.Remaining())
+	}
+}
+
+// Test 2: Matching options
+func TestMatching(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	cassette := &Cassette{
+		Version: Version,
+		Interactions: []Interaction{{
+			Request:  Request{Method: "GET", Path: "/v1/goals", Query: "limit=1"},
+			Response: Response{StatusCode: 200, Body: `{"goals": [{"name": "dummy"}]}`},
+		}},
+	}
+	if err := cassette.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	strict, err := New(path, Options{Mode: ModeReplay})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	client := watcherclient.NewClientWithToken("http://watcher.invalid", "token")
+	client.SetTransport(strict)
+
+	if _, err := client.ListGoals(&watcherclient.ListOptions{Limit: 2}); !errors.Is(err, ErrNoMatch) {
+		t.Errorf("Expected ErrNoMatch for a different query, got %v", err)
+	}
+
+	loose, err := New(path, Options{Mode: ModeReplay, Match: &MatchOptions{Method: true, Path: true}})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	client.SetTransport(loose)
+
+	goals, err := client.ListGoals(&watcherclient.ListOptions{Limit: 2})
+	if err != nil {
+		t.Fatalf("ListGoals failed: %v", err)
+	}
+	if len(goals) != 1 || goals[0].Name != "dummy" {
+		t.Errorf("Expected dummy goal, got %+v", goals)
+	}
+}
+
+// Test 3: Recording through the client transport keeps its TLS settings,
+// leaves requests untouched and saves private cassettes without tokens
+func TestRecordClientTransport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	server := watchertest.NewServer(watchertest.Options{TLS: true})
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, server.CACertPEM(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	opts := server.ClientOptions()
+	opts.CACert = caFile
+	client, err := watcherclient.NewClient(opts)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	rec, err := New(path, Options{Mode: ModeRecord, RedactHeaders: []string{"Cookie"}})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	rec.Install(client)
+
+	if _, err := client.CreateAudit(&watcherclient.Audit{AuditType: "ONESHOT", Goal: "dummy"}); err != nil {
+		t.Fatalf("CreateAudit through the recorder failed: %v", err)
+	}
+
+	body := io.NopCloser(strings.NewReader(`{"audit_type": "ONESHOT"}`))
+	req, err := http.NewRequest("POST", server.Endpoint()+"/v1/audits", body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-Auth-Token", server.Token())
+	resp, err := rec.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip failed: %v", err)
+	}
+	resp.Body.Close()
+	if req.Body != body {
+		t.Error("Expected the caller's request body to be left untouched")
+	}
+
+	if err := rec.Stop(); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat failed: %v", err)
+	}
+	if mode := info.Mode().Perm(); mode != 0o600 {
+		t.Errorf("Expected cassette mode 0600, got %o", mode)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if strings.Contains(string(data), server.Token()) {
+		t.Error("Expected token headers to be redacted despite custom RedactHeaders")
+	}
+}
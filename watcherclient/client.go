@@ -5,10 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"strings"
 	"time"
-
-	"github.com/gophercloud/gophercloud/v2"
 )
 
 const (
@@ -22,19 +22,65 @@ type Client struct {
 	authenticator interface{ GetToken() (string, error) }
 	httpClient    *http.Client
 	apiVersion    string
+	logger        *slog.Logger
+	logBodies     bool
 }
 
 // ClientOptions represents client configuration options
 type ClientOptions struct {
-	AuthURL         string
-	Username        string
-	Password        string
-	ProjectName     string
-	ProjectDomainID string
-	UserDomainID    string
-	Region          string
-	Timeout         time.Duration
-	AllowReauth     bool // Enable automatic re-authentication
+	AuthURL           string
+	Username          string
+	UserID            string
+	Password          string
+	ProjectName       string
+	ProjectID         string
+	ProjectDomainID   string
+	ProjectDomainName string
+	UserDomainID      string
+	UserDomainName    string
+	DomainID          string // Domain scope, used when no project is given
+	DomainName        string
+	SystemScope       string // "all" requests a system-scoped token
+	Token             string // Existing Keystone token, used instead of a password
+	Region            string
+	Interface         string // Catalog interface: public (default), internal or admin
+	ServiceName       string // Catalog service name, when several Watcher services exist
+	EndpointOverride  string // Watcher URL used instead of the catalog, with Keystone tokens
+	CACert            string // PEM file of the CAs trusted for Keystone and Watcher, reloaded when it changes
+	ClientCert        string // PEM client certificate for mutual TLS
+	ClientKey         string // PEM key of ClientCert
+	Insecure          bool   // Skip TLS verification, for labs only
+	Proxy             string // HTTP proxy URL; the HTTPS_PROXY/NO_PROXY environment is used when empty
+	Timeout           time.Duration
+	AllowReauth       bool       // Enable automatic re-authentication
+	TokenCache        TokenCache // Consulted before authenticating, see FileTokenCache
+
+	// Token refresh. With BackgroundRefresh, tokens are renewed by a
+	// goroutine RefreshLeadTime before they expire; call Close to stop it.
+	BackgroundRefresh bool
+	RefreshLeadTime   time.Duration
+	OnRefresh         func(RefreshEvent)
+
+	// Multi-factor and federated authentication: a TOTP passcode sent with
+	// the password, or a callback returning a Keystone token, called again
+	// when the token nears expiry
+	Passcode      string
+	TokenProvider func() (string, error)
+
+	// OnWarning receives non-fatal authentication problems, such as a
+	// token without a known expiry
+	OnWarning func(message string)
+
+	// Logger receives request, re-authentication and token refresh logs.
+	// Tokens and secrets are redacted; LogBodies adds the request and
+	// response bodies at debug level.
+	Logger    *slog.Logger
+	LogBodies bool
+
+	// Application credentials, used instead of a password
+	ApplicationCredentialID     string
+	ApplicationCredentialName   string
+	ApplicationCredentialSecret string
 }
 
 // NewClient creates a new Watcher client with Keystone authentication
@@ -43,18 +89,18 @@ func NewClient(opts ClientOptions) (*Client, error) {
 		opts.Timeout = DefaultTimeout
 	}
 
+	transport, err := NewTransport(opts)
+	if err != nil {
+		return nil, err
+	}
+
 	// Build authentication options
-	authOpts := &AuthOptions{
-		IdentityEndpoint: opts.AuthURL,
-		Username:         opts.Username,
-		Password:         opts.Password,
-		DomainID:         opts.UserDomainID,
-		AllowReauth:      opts.AllowReauth,
-		Scope: &gophercloud.AuthScope{
-			ProjectName: opts.ProjectName,
-			DomainID:    opts.ProjectDomainID,
-		},
+	if err := validateScopeOptions(opts); err != nil {
+		return nil, fmt.Errorf("invalid auth options: %w", err)
 	}
+	authOpts := BuildAuthOptions(opts)
+	authOpts.AllowReauth = opts.AllowReauth && authOpts.AllowReauth
+	authOpts.Transport = transport
 
 	// Validate auth options
 	if err := ValidateAuthOptions(authOpts); err != nil {
@@ -71,9 +117,12 @@ func NewClient(opts ClientOptions) (*Client, error) {
 		endpoint:      auth.GetEndpoint() + "/" + DefaultAPIVersion,
 		authenticator: auth,
 		httpClient: &http.Client{
-			Timeout: opts.Timeout,
+			Timeout:   opts.Timeout,
+			Transport: transport,
 		},
 		apiVersion: DefaultAPIVersion,
+		logger:     opts.Logger,
+		logBodies:  opts.LogBodies,
 	}
 
 	return client, nil
@@ -91,6 +140,44 @@ func NewClientWithToken(endpoint, token string) *Client {
 	}
 }
 
+// NewClientNoAuth creates a client for a Watcher running without
+// authentication, for development and integration tests
+func NewClientNoAuth(endpoint string) *Client {
+	return NewClientWithAuthenticator(NewNoAuthAuthenticator(endpoint, ""))
+}
+
+// NewClientNoAuthWithOptions creates a client for a Watcher running without
+// authentication at opts.EndpointOverride, acting on opts.ProjectID when
+// set. Transport, timeout and logging options apply as with NewClient;
+// Keystone options are ignored.
+func NewClientNoAuthWithOptions(opts ClientOptions) (*Client, error) {
+	if opts.EndpointOverride == "" {
+		return nil, fmt.Errorf("endpoint override is required without authentication")
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = DefaultTimeout
+	}
+
+	transport, err := NewTransport(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := strings.TrimSuffix(opts.EndpointOverride, "/")
+	endpoint = strings.TrimSuffix(endpoint, "/"+DefaultAPIVersion)
+	return &Client{
+		endpoint:      endpoint + "/" + DefaultAPIVersion,
+		authenticator: NewNoAuthAuthenticator(endpoint, opts.ProjectID),
+		httpClient: &http.Client{
+			Timeout:   opts.Timeout,
+			Transport: transport,
+		},
+		apiVersion: DefaultAPIVersion,
+		logger:     opts.Logger,
+		logBodies:  opts.LogBodies,
+	}, nil
+}
+
 // NewClientWithAuthenticator creates a client with a custom authenticator
 func NewClientWithAuthenticator(auth interface {
 	GetToken() (string, error)
@@ -108,6 +195,12 @@ func NewClientWithAuthenticator(auth interface {
 
 // doRequest performs an HTTP request with automatic token handling
 func (c *Client) doRequest(method, path string, body interface{}) (*http.Response, error) {
+	return c.doRequestAttempt(method, path, body, 1)
+}
+
+// doRequestAttempt performs a request; attempt counts the retries after
+// re-authentication
+func (c *Client) doRequestAttempt(method, path string, body interface{}, attempt int) (*http.Response, error) {
 	// Get current valid token
 	token, err := c.authenticator.GetToken()
 	if err != nil {
@@ -115,8 +208,9 @@ func (c *Client) doRequest(method, path string, body interface{}) (*http.Respons
 	}
 
 	var bodyReader io.Reader
+	var jsonData []byte
 	if body != nil {
-		jsonData, err := json.Marshal(body)
+		jsonData, err = json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal request body: %w", err)
 		}
@@ -128,8 +222,16 @@ func (c *Client) doRequest(method, path string, body interface{}) (*http.Respons
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	// Set headers
-	req.Header.Set("X-Auth-Token", token)
+	// Set headers. Noauth deployments get no token but may need headers
+	// identifying the caller.
+	if token != "" {
+		req.Header.Set("X-Auth-Token", token)
+	}
+	if auth, ok := c.authenticator.(interface{ AuthHeaders() map[string]string }); ok {
+		for name, value := range auth.AuthHeaders() {
+			req.Header.Set(name, value)
+		}
+	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("User-Agent", "golang-watcherclient/1.0")
@@ -137,21 +239,24 @@ func (c *Client) doRequest(method, path string, body interface{}) (*http.Respons
 	// Set API version header if needed
 	req.Header.Set("OpenStack-API-Version", "infra-optim "+c.apiVersion)
 
+	start := time.Now()
 	resp, err := c.httpClient.Do(req)
+	c.logRequest(req, path, resp, jsonData, start, attempt, err)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 
 	// Handle authentication errors
 	if resp.StatusCode == http.StatusUnauthorized {
-		// Try to re-authenticate if using full authenticator
-		if auth, ok := c.authenticator.(*Authenticator); ok && auth.autoReauth {
+		// Try to re-authenticate once if using full authenticator
+		if auth, ok := c.authenticator.(*Authenticator); ok && auth.canReauth() && attempt == 1 {
 			resp.Body.Close()
+			c.logInfo("re-authenticating after 401", slog.String("method", method), slog.String("path", path))
 			if err := auth.Reauth(); err != nil {
 				return nil, fmt.Errorf("re-authentication failed: %w", err)
 			}
 			// Retry the request with new token
-			return c.doRequest(method, path, body)
+			return c.doRequestAttempt(method, path, body, attempt+1)
 		}
 		defer resp.Body.Close()
 		return nil, fmt.Errorf("authentication failed: token expired or invalid")
@@ -202,6 +307,30 @@ func (c *Client) GetTimeout() time.Duration {
 	return c.httpClient.Timeout
 }
 
+// SetTransport sets the HTTP transport used for Watcher requests, e.g. to
+// record or replay traffic. A nil transport restores http.DefaultTransport.
+func (c *Client) SetTransport(rt http.RoundTripper) {
+	c.httpClient.Transport = rt
+}
+
+// GetTransport returns the HTTP transport used for Watcher requests
+func (c *Client) GetTransport() http.RoundTripper {
+	if c.httpClient.Transport == nil {
+		return http.DefaultTransport
+	}
+	return c.httpClient.Transport
+}
+
+// SetLogger sets the logger of the client, nil disables logging
+func (c *Client) SetLogger(logger *slog.Logger) {
+	c.logger = logger
+}
+
+// GetLogger returns the logger of the client
+func (c *Client) GetLogger() *slog.Logger {
+	return c.logger
+}
+
 // GetEndpoint returns the Watcher API endpoint
 func (c *Client) GetEndpoint() string {
 	return c.endpoint
@@ -217,6 +346,14 @@ func (c *Client) SetAPIVersion(version string) {
 	c.apiVersion = version
 }
 
+// Close stops the background token refresher, if any
+func (c *Client) Close() error {
+	if closer, ok := c.authenticator.(interface{ Close() error }); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
 // GetAuthInfo returns authentication information (if using full authenticator)
 func (c *Client) GetAuthInfo() (*AuthInfo, error) {
 	if auth, ok := c.authenticator.(*Authenticator); ok {
@@ -312,7 +449,7 @@ type ClientConfig struct {
 	RetryCount    int
 	RetryWaitTime time.Duration
 	MaxRetryWait  time.Duration
-	Debug         bool
+	Debug         bool // Log request and response bodies, to slog.Default without Logger
 	CustomHeaders map[string]string
 }
 
@@ -326,6 +463,12 @@ func NewClientWithConfig(opts ClientOptions, config ClientConfig) (*Client, erro
 	if config.Timeout > 0 {
 		client.SetTimeout(config.Timeout)
 	}
+	if config.Debug {
+		if client.logger == nil {
+			client.logger = slog.Default()
+		}
+		client.logBodies = true
+	}
 
 	// TODO: Implement retry logic and custom headers if needed
 
@@ -1,8 +1,15 @@
 package watcherclient
 
 import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/openstack/identity/v3/tokens"
 )
 
 // Test 1: Client oluşturma (token ile)
@@ -355,3 +362,349 @@ func TestActionPlanStruct(t *testing.T) {
 		t.Errorf("Expected state RECOMMENDED, got %s", plan.State)
 	}
 }
+
+// Test 21: Compute data model decoding
+func TestParseComputeDataModel(t *testing.T) {
+	dm := &DataModel{
+		Context: []map[string]interface{}{
+			{
+				"node_uuid":         "node-1",
+				"node_hostname":     "compute-1",
+				"node_vcpus":        float64(32),
+				"node_memory":       float64(65536),
+				"node_state":        "up",
+				"node_status":       "enabled",
+				"node_new_field":    "x",
+				"server_uuid":       "vm-1",
+				"server_vcpus":      float64(4),
+				"server_memory":     float64(8192),
+				"server_project_id": "project-1",
+				"server_state":      "active",
+				"server_metadata":   nil,
+			},
+			{
+				"node_uuid":     "node-1",
+				"node_hostname": "compute-1",
+				"server_uuid":   "vm-2",
+				"server_vcpus":  float64(2),
+			},
+			{
+				"node_uuid":     "node-2",
+				"node_hostname": "compute-2",
+				"node_vcpus":    float64(16),
+			},
+		},
+	}
+
+	model, err := ParseComputeDataModel(dm)
+	if err != nil {
+		t.Fatalf("ParseComputeDataModel failed: %v", err)
+	}
+
+	if len(model.Nodes) != 2 {
+		t.Fatalf("Expected 2 nodes, got %d", len(model.Nodes))
+	}
+
+	if len(model.Instances) != 2 {
+		t.Fatalf("Expected 2 instances, got %d", len(model.Instances))
+	}
+
+	if model.Instances[0].Host != "compute-1" || model.Instances[0].VCPUs != 4 {
+		t.Errorf("Unexpected instance: %+v", model.Instances[0])
+	}
+
+	if model.Nodes[0].Extra["new_field"] != "x" {
+		t.Errorf("Expected unknown field in Extra, got %v", model.Nodes[0].Extra)
+	}
+
+	if len(model.InstancesOn("node-2")) != 0 {
+		t.Error("Expected no instances on node-2")
+	}
+
+	if _, ok := model.Node("compute-2"); !ok {
+		t.Error("Expected to find node by hostname")
+	}
+}
+
+// Test 22: Client options from OS_* environment variables
+func TestClientOptionsFromEnv(t *testing.T) {
+	env := map[string]string{
+		"OS_AUTH_URL":                      "https://keystone:5000/v3",
+		"OS_TENANT_NAME":                   "legacy",
+		"OS_APPLICATION_CREDENTIAL_ID":     "app-id",
+		"OS_APPLICATION_CREDENTIAL_SECRET": "app-secret",
+		"OS_REGION_NAME":                   "RegionTwo",
+		"OS_INTERFACE":                     "internalURL",
+	}
+	opts := clientOptionsFromEnv(func(name string) string { return env[name] })
+
+	if opts.ProjectName != "legacy" {
+		t.Errorf("Expected project legacy, got %s", opts.ProjectName)
+	}
+	if opts.Region != "RegionTwo" || opts.Interface != "internalURL" {
+		t.Errorf("Expected RegionTwo/internalURL, got %s/%s", opts.Region, opts.Interface)
+	}
+	if availability(opts.Interface) != "internal" {
+		t.Errorf("Expected internal availability, got %s", availability(opts.Interface))
+	}
+
+	authOpts := BuildAuthOptions(opts)
+	if authOpts.ApplicationCredentialID != "app-id" || authOpts.Scope != nil {
+		t.Errorf("Expected unscoped application credential auth, got %+v", authOpts)
+	}
+	if err := ValidateAuthOptions(authOpts); err != nil {
+		t.Errorf("Expected valid auth options, got %v", err)
+	}
+}
+
+// Test 23: Client options from clouds.yaml merged with secure.yaml
+func TestClientOptionsFromCloud(t *testing.T) {
+	dir := t.TempDir()
+	cloudsYAML := `clouds:
+  lab:
+    auth:
+      auth_url: https://keystone.lab:5000/v3
+      username: operator
+      project_name: infra
+      user_domain_name: Default
+      domain_id: lab-domain
+    region_name: RegionOne
+    interface: internal
+    cacert: /etc/ssl/lab-ca.pem
+`
+	secureYAML := `clouds:
+  lab:
+    auth:
+      password: s3cret
+`
+	if err := os.WriteFile(filepath.Join(dir, "clouds.yaml"), []byte(cloudsYAML), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "secure.yaml"), []byte(secureYAML), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	env := map[string]string{
+		"OS_CLOUD":              "lab",
+		"OS_CLIENT_CONFIG_FILE": filepath.Join(dir, "clouds.yaml"),
+		"OS_CLIENT_SECURE_FILE": filepath.Join(dir, "secure.yaml"),
+		"OS_REGION_NAME":        "RegionTwo",
+	}
+	opts, err := clientOptionsFromCloud("", func(name string) string { return env[name] })
+	if err != nil {
+		t.Fatalf("clientOptionsFromCloud failed: %v", err)
+	}
+
+	if opts.AuthURL != "https://keystone.lab:5000/v3" || opts.Password != "s3cret" {
+		t.Errorf("Expected merged auth URL and password, got %+v", opts)
+	}
+	if opts.UserDomainName != "Default" || opts.UserDomainID != "" {
+		t.Errorf("Expected user domain name Default, got %s/%s", opts.UserDomainID, opts.UserDomainName)
+	}
+	if opts.ProjectDomainID != "lab-domain" {
+		t.Errorf("Expected project domain lab-domain, got %s", opts.ProjectDomainID)
+	}
+	if opts.Region != "RegionTwo" || opts.Interface != "internal" || opts.CACert != "/etc/ssl/lab-ca.pem" {
+		t.Errorf("Unexpected region, interface or CA: %+v", opts)
+	}
+
+	if _, err := clientOptionsFromCloud("missing", func(name string) string { return env[name] }); err == nil {
+		t.Error("Expected error for unknown cloud")
+	}
+}
+
+// Test 24: Scope selection and validation
+func TestBuildAuthOptionsScope(t *testing.T) {
+	base := ClientOptions{AuthURL: "https://keystone:5000/v3", Username: "admin", Password: "secret", UserDomainID: "default"}
+
+	system := base
+	system.SystemScope = "all"
+	system.ProjectName = "ignored"
+	if scope := BuildAuthOptions(system).Scope; scope == nil || !scope.System || scope.ProjectName != "" {
+		t.Errorf("Expected system scope, got %+v", scope)
+	}
+
+	domain := base
+	domain.DomainName = "infra"
+	if scope := BuildAuthOptions(domain).Scope; scope == nil || scope.DomainName != "infra" || scope.ProjectName != "" {
+		t.Errorf("Expected domain scope, got %+v", scope)
+	}
+
+	token := ClientOptions{AuthURL: base.AuthURL, Token: "gAAAAtoken", ProjectID: "p1"}
+	authOpts := BuildAuthOptions(token)
+	if authOpts.TokenID != "gAAAAtoken" || authOpts.Scope.ProjectID != "p1" {
+		t.Errorf("Expected token auth scoped to p1, got %+v", authOpts)
+	}
+	if err := ValidateAuthOptions(authOpts); err != nil {
+		t.Errorf("Expected valid token auth, got %v", err)
+	}
+
+	invalid := BuildAuthOptions(base)
+	invalid.Scope = &gophercloud.AuthScope{ProjectName: "admin"}
+	if err := ValidateAuthOptions(invalid); err == nil {
+		t.Error("Expected error for project name without domain")
+	}
+
+	invalid.Scope = &gophercloud.AuthScope{System: true, DomainID: "default"}
+	if err := ValidateAuthOptions(invalid); err == nil {
+		t.Error("Expected error for system scope with a domain")
+	}
+
+	projectDomain := domain
+	projectDomain.ProjectDomainID = "default"
+	if scope := BuildAuthOptions(projectDomain).Scope; scope == nil || scope.DomainName != "infra" || scope.DomainID != "" {
+		t.Errorf("Expected domain scope without a project, got %+v", scope)
+	}
+	if err := validateScopeOptions(projectDomain); err == nil {
+		t.Error("Expected error for project domain without project")
+	}
+	if _, err := NewClient(projectDomain); err == nil || !strings.Contains(err.Error(), "project domain") {
+		t.Errorf("Expected NewClient to reject project domain without project, got %v", err)
+	}
+}
+
+// Test 25: File token cache with encryption and permission checks
+func TestFileTokenCache(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "tokens")
+	cache, err := NewFileTokenCache(dir, "passphrase")
+	if err != nil {
+		t.Fatalf("NewFileTokenCache failed: %v", err)
+	}
+
+	if _, err := cache.Load("key"); err != ErrTokenNotCached {
+		t.Errorf("Expected ErrTokenNotCached, got %v", err)
+	}
+
+	token := CachedToken{Token: "gAAAA-secret", ExpiresAt: time.Now().Add(time.Hour).UTC(), Endpoint: "http://watcher:9322"}
+	if err := cache.Store("key", token); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+
+	loaded, err := cache.Load("key")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if loaded.Token != token.Token || !loaded.ExpiresAt.Equal(token.ExpiresAt) || loaded.Endpoint != token.Endpoint {
+		t.Errorf("Expected %+v, got %+v", token, loaded)
+	}
+
+	data, err := os.ReadFile(cache.path("key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "gAAAA-secret") {
+		t.Error("Expected encrypted token on disk")
+	}
+
+	wrongKey := &FileTokenCache{Dir: dir, Key: TokenCacheKey("other")}
+	if _, err := wrongKey.Load("key"); err == nil {
+		t.Error("Expected error with the wrong key")
+	}
+
+	if err := os.Chmod(cache.path("key"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.Load("key"); err == nil {
+		t.Error("Expected error for a world-readable cache file")
+	}
+
+	project := &AuthOptions{IdentityEndpoint: "https://keystone/v3", Username: "admin", Scope: &gophercloud.AuthScope{ProjectID: "p1"}}
+	other := &AuthOptions{IdentityEndpoint: "https://keystone/v3", Username: "admin", Scope: &gophercloud.AuthScope{ProjectID: "p2"}}
+	if tokenCacheKey(project) == tokenCacheKey(other) {
+		t.Error("Expected different cache keys for different projects")
+	}
+
+	appCred := &AuthOptions{IdentityEndpoint: "https://keystone/v3", UserID: "u1", ApplicationCredentialName: "ci", ApplicationCredentialSecret: "s1"}
+	rotated := *appCred
+	rotated.ApplicationCredentialSecret = "s2"
+	if key := tokenCacheKey(appCred); key == tokenCacheKey(&rotated) || strings.Contains(key, "s1") {
+		t.Error("Expected cache keys to differ by a digest of the application credential secret")
+	}
+}
+
+// Test 26: Token identity parsed from an authentication result
+func TestIdentityFromResult(t *testing.T) {
+	var result tokens.CreateResult
+	result.Body = map[string]interface{}{
+		"token": map[string]interface{}{
+			"issued_at":  "2026-01-01T10:00:00.000000Z",
+			"expires_at": "2026-01-01T11:00:00.000000Z",
+			"user":       map[string]interface{}{"id": "u1", "name": "admin", "domain": map[string]interface{}{"id": "default", "name": "Default"}},
+			"project":    map[string]interface{}{"id": "p1", "name": "admin"},
+			"roles":      []interface{}{map[string]interface{}{"id": "r1", "name": "admin"}},
+			"catalog": []interface{}{map[string]interface{}{
+				"type": "infra-optim", "name": "watcher",
+				"endpoints": []interface{}{map[string]interface{}{"region": "RegionOne", "interface": "public", "url": "http://watcher:9322"}},
+			}},
+		},
+	}
+
+	identity, err := identityFromResult(result)
+	if err != nil {
+		t.Fatalf("identityFromResult failed: %v", err)
+	}
+	if identity.ExpiresAt.Sub(identity.IssuedAt) != time.Hour {
+		t.Errorf("Expected a one hour token, got %v to %v", identity.IssuedAt, identity.ExpiresAt)
+	}
+	if identity.UserID != "u1" || identity.UserDomainName != "Default" || identity.ProjectID != "p1" {
+		t.Errorf("Unexpected user or project: %+v", identity)
+	}
+	if len(identity.Roles) != 1 || identity.Roles[0] != "admin" {
+		t.Errorf("Expected role admin, got %v", identity.Roles)
+	}
+	if len(identity.Catalog) != 1 || identity.Catalog[0].Endpoints[0].URL != "http://watcher:9322" {
+		t.Errorf("Unexpected catalog: %+v", identity.Catalog)
+	}
+
+	// Unscoped token authentication validates the token instead
+	var validated tokens.GetResult
+	validated.Body = result.Body
+	if identity, err := identityFromResult(validated); err != nil || identity.ExpiresAt.IsZero() || identity.UserID != "u1" {
+		t.Errorf("Expected identity of a validated token, got %+v, %v", identity, err)
+	}
+
+	if _, err := identityFromResult(nil); err == nil {
+		t.Error("Expected error without an authentication result")
+	}
+}
+
+// Test 27: Token provider running a command
+func TestCommandTokenProvider(t *testing.T) {
+	token, err := CommandTokenProvider("echo", "gAAAA-federated")()
+	if err != nil {
+		t.Fatalf("CommandTokenProvider failed: %v", err)
+	}
+	if token != "gAAAA-federated" {
+		t.Errorf("Expected gAAAA-federated, got %q", token)
+	}
+
+	if _, err := CommandTokenProvider("false")(); err == nil {
+		t.Error("Expected error for a failing command")
+	}
+	if _, err := CommandTokenProvider("true")(); err == nil {
+		t.Error("Expected error for an empty token")
+	}
+}
+
+// Test 28: Redaction of logged headers and bodies
+func TestRedaction(t *testing.T) {
+	headers := http.Header{}
+	headers.Set("X-Auth-Token", "gAAAA-secret-token")
+	headers.Set("Accept", "application/json")
+
+	redacted := redactHeaders(headers)
+	if strings.Contains(redacted.Get("X-Auth-Token"), "gAAAA") || !strings.HasPrefix(redacted.Get("X-Auth-Token"), "{SHA256}") {
+		t.Errorf("Expected hashed token, got %s", redacted.Get("X-Auth-Token"))
+	}
+	if redacted.Get("Accept") != "application/json" || headers.Get("X-Auth-Token") != "gAAAA-secret-token" {
+		t.Error("Expected other headers and the original to be unchanged")
+	}
+
+	body := redactBody([]byte(`{"name":"nightly","auth":{"identity":{"password":{"user":{"password":"s3cret"}}}},"application_credential_secret":"x"}`))
+	if strings.Contains(body, "s3cret") || strings.Contains(body, `"x"`) || !strings.Contains(body, "nightly") {
+		t.Errorf("Expected secrets redacted, got %s", body)
+	}
+	if redactBody([]byte("not json")) != "<non-JSON body omitted>" {
+		t.Error("Expected non-JSON bodies to be omitted")
+	}
+}
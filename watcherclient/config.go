@@ -0,0 +1,336 @@
+package watcherclient
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/gophercloud/gophercloud/v2/openstack/config/clouds"
+	"gopkg.in/yaml.v2"
+)
+
+// envLookup returns the value of the first non-empty variable
+func envLookup(getenv func(string) string, names ...string) string {
+	for _, name := range names {
+		if v := getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// ClientOptionsFromEnv returns client options read from the standard OS_*
+// environment variables
+func ClientOptionsFromEnv() ClientOptions {
+	return clientOptionsFromEnv(os.Getenv)
+}
+
+// clientOptionsFromEnv reads client options through getenv
+func clientOptionsFromEnv(getenv func(string) string) ClientOptions {
+	opts := ClientOptions{
+		AuthURL:                     getenv("OS_AUTH_URL"),
+		Username:                    getenv("OS_USERNAME"),
+		UserID:                      getenv("OS_USER_ID"),
+		Password:                    getenv("OS_PASSWORD"),
+		ProjectName:                 envLookup(getenv, "OS_PROJECT_NAME", "OS_TENANT_NAME"),
+		ProjectID:                   envLookup(getenv, "OS_PROJECT_ID", "OS_TENANT_ID"),
+		ProjectDomainID:             getenv("OS_PROJECT_DOMAIN_ID"),
+		ProjectDomainName:           getenv("OS_PROJECT_DOMAIN_NAME"),
+		UserDomainID:                getenv("OS_USER_DOMAIN_ID"),
+		UserDomainName:              getenv("OS_USER_DOMAIN_NAME"),
+		DomainID:                    getenv("OS_DOMAIN_ID"),
+		DomainName:                  getenv("OS_DOMAIN_NAME"),
+		SystemScope:                 getenv("OS_SYSTEM_SCOPE"),
+		Token:                       getenv("OS_TOKEN"),
+		Passcode:                    getenv("OS_PASSCODE"),
+		ApplicationCredentialID:     getenv("OS_APPLICATION_CREDENTIAL_ID"),
+		ApplicationCredentialName:   getenv("OS_APPLICATION_CREDENTIAL_NAME"),
+		ApplicationCredentialSecret: getenv("OS_APPLICATION_CREDENTIAL_SECRET"),
+		Region:                      getenv("OS_REGION_NAME"),
+		Interface:                   getenv("OS_INTERFACE"),
+		ServiceName:                 getenv("OS_INFRA_OPTIM_SERVICE_NAME"),
+		EndpointOverride:            getenv("OS_INFRA_OPTIM_ENDPOINT_OVERRIDE"),
+		CACert:                      getenv("OS_CACERT"),
+		ClientCert:                  getenv("OS_CERT"),
+		ClientKey:                   getenv("OS_KEY"),
+		Insecure:                    envBool(getenv("OS_INSECURE")),
+	}
+	opts.AllowReauth = BuildAuthOptions(opts).AllowReauth
+	return opts
+}
+
+// envBool parses a boolean environment value, false when invalid
+func envBool(value string) bool {
+	b, _ := strconv.ParseBool(value)
+	return b
+}
+
+// NewClientFromEnv creates a client from the OS_* environment variables.
+// When OS_AUTH_URL is unset and OS_CLOUD is set, the named cloud is read
+// from clouds.yaml instead. OS_AUTH_TYPE=none creates a noauth client for
+// OS_INFRA_OPTIM_ENDPOINT_OVERRIDE.
+func NewClientFromEnv() (*Client, error) {
+	if authType := os.Getenv("OS_AUTH_TYPE"); authType == "none" || authType == "noauth" {
+		// OS_CACERT, OS_CERT, OS_KEY and OS_INSECURE apply without Keystone
+		opts := clientOptionsFromEnv(os.Getenv)
+		if opts.EndpointOverride == "" {
+			return nil, fmt.Errorf("OS_INFRA_OPTIM_ENDPOINT_OVERRIDE is required with OS_AUTH_TYPE=%s", authType)
+		}
+		return NewClientNoAuthWithOptions(opts)
+	}
+	if os.Getenv("OS_AUTH_URL") == "" && os.Getenv("OS_CLOUD") != "" {
+		return NewClientFromCloud("")
+	}
+	return NewClient(ClientOptionsFromEnv())
+}
+
+// NewClientFromCloud creates a client from a cloud of clouds.yaml, merged
+// with secure.yaml. An empty name uses OS_CLOUD.
+func NewClientFromCloud(name string) (*Client, error) {
+	opts, err := ClientOptionsFromCloud(name)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(opts)
+}
+
+// ClientOptionsFromCloud returns the client options of a cloud of
+// clouds.yaml. The files are searched like the OpenStack SDK does:
+// OS_CLIENT_CONFIG_FILE (and OS_CLIENT_SECURE_FILE), then the current
+// directory, the user config directory and /etc/openstack. OS_REGION_NAME,
+// OS_INTERFACE, OS_CACERT, OS_CERT and OS_KEY override the cloud settings.
+func ClientOptionsFromCloud(name string) (ClientOptions, error) {
+	return clientOptionsFromCloud(name, os.Getenv)
+}
+
+// clientOptionsFromCloud reads the options of a cloud through getenv
+func clientOptionsFromCloud(name string, getenv func(string) string) (ClientOptions, error) {
+	if name == "" {
+		name = getenv("OS_CLOUD")
+	}
+	if name == "" {
+		return ClientOptions{}, fmt.Errorf("cloud name is required, set OS_CLOUD")
+	}
+
+	cloudsPath := getenv("OS_CLIENT_CONFIG_FILE")
+	if cloudsPath == "" {
+		cloudsPath = findConfigFile("clouds")
+	}
+	if cloudsPath == "" {
+		return ClientOptions{}, fmt.Errorf("clouds.yaml not found in %v", configDirs())
+	}
+
+	merged, err := readYAMLFile(cloudsPath)
+	if err != nil {
+		return ClientOptions{}, err
+	}
+
+	securePath := getenv("OS_CLIENT_SECURE_FILE")
+	if securePath == "" {
+		securePath = findConfigFile("secure")
+	}
+	if securePath != "" {
+		secure, err := readYAMLFile(securePath)
+		if err != nil {
+			return ClientOptions{}, err
+		}
+		merged = mergeYAML(merged, secure)
+	}
+
+	// Decode the merged document with the gophercloud clouds.yaml schema
+	data, err := yaml.Marshal(merged)
+	if err != nil {
+		return ClientOptions{}, fmt.Errorf("failed to encode cloud configuration: %w", err)
+	}
+	var config clouds.Clouds
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return ClientOptions{}, fmt.Errorf("failed to parse %s: %w", cloudsPath, err)
+	}
+
+	cloud, ok := config.Clouds[name]
+	if !ok {
+		return ClientOptions{}, fmt.Errorf("cloud '%s' not found in %s", name, cloudsPath)
+	}
+
+	opts := cloudClientOptions(cloud, getenv)
+
+	// Per-service settings are not part of the gophercloud schema
+	if raw, ok := lookupYAML(merged, "clouds", name); ok {
+		if v, ok := raw["infra_optim_service_name"].(string); ok {
+			opts.ServiceName = v
+		}
+		if v, ok := raw["infra_optim_endpoint_override"].(string); ok {
+			opts.EndpointOverride = v
+		}
+	}
+	if v := getenv("OS_INFRA_OPTIM_ENDPOINT_OVERRIDE"); v != "" {
+		opts.EndpointOverride = v
+	}
+
+	return opts, nil
+}
+
+// lookupYAML follows keys through nested YAML maps
+func lookupYAML(doc map[interface{}]interface{}, keys ...string) (map[interface{}]interface{}, bool) {
+	current := doc
+	for _, key := range keys {
+		next, ok := current[key].(map[interface{}]interface{})
+		if !ok {
+			return nil, false
+		}
+		current = next
+	}
+	return current, true
+}
+
+// cloudClientOptions converts a clouds.yaml entry to client options
+func cloudClientOptions(cloud clouds.Cloud, getenv func(string) string) ClientOptions {
+	auth := cloud.AuthInfo
+	if auth == nil {
+		auth = &clouds.AuthInfo{}
+	}
+
+	projectDomainID, projectDomainName := cloudDomain(auth.ProjectDomainID, auth.ProjectDomainName, auth)
+	userDomainID, userDomainName := cloudDomain(auth.UserDomainID, auth.UserDomainName, auth)
+
+	opts := ClientOptions{
+		AuthURL:                     auth.AuthURL,
+		Username:                    auth.Username,
+		UserID:                      auth.UserID,
+		Password:                    auth.Password,
+		ProjectName:                 auth.ProjectName,
+		ProjectID:                   auth.ProjectID,
+		ProjectDomainID:             projectDomainID,
+		ProjectDomainName:           projectDomainName,
+		UserDomainID:                userDomainID,
+		UserDomainName:              userDomainName,
+		SystemScope:                 auth.SystemScope,
+		Token:                       auth.Token,
+		ApplicationCredentialID:     auth.ApplicationCredentialID,
+		ApplicationCredentialName:   auth.ApplicationCredentialName,
+		ApplicationCredentialSecret: auth.ApplicationCredentialSecret,
+		Region:                      cloud.RegionName,
+		Interface:                   firstNonEmpty(cloud.Interface, cloud.EndpointType),
+		CACert:                      cloud.CACertFile,
+		ClientCert:                  cloud.ClientCertFile,
+		ClientKey:                   cloud.ClientKeyFile,
+		Insecure:                    cloud.Verify != nil && !*cloud.Verify,
+	}
+
+	// Without a project, domain_id and domain_name select a domain scope
+	if auth.ProjectID == "" && auth.ProjectName == "" {
+		opts.ProjectDomainID, opts.ProjectDomainName = "", ""
+		opts.DomainID, opts.DomainName = auth.DomainID, auth.DomainName
+	}
+
+	// The first region is used when the cloud lists several
+	if opts.Region == "" && len(cloud.Regions) > 0 {
+		opts.Region = cloud.Regions[0].Name
+	}
+
+	// A name and an ID for the same domain are redundant for Keystone
+	if opts.ProjectDomainID != "" && opts.ProjectDomainName != "" {
+		opts.ProjectDomainName = ""
+	}
+	if opts.UserDomainID != "" && opts.UserDomainName != "" {
+		opts.UserDomainName = ""
+	}
+
+	if v := getenv("OS_REGION_NAME"); v != "" {
+		opts.Region = v
+	}
+	if v := getenv("OS_INTERFACE"); v != "" {
+		opts.Interface = v
+	}
+	if v := getenv("OS_CACERT"); v != "" {
+		opts.CACert = v
+	}
+	if v := getenv("OS_CERT"); v != "" {
+		opts.ClientCert = v
+	}
+	if v := getenv("OS_KEY"); v != "" {
+		opts.ClientKey = v
+	}
+
+	opts.AllowReauth = BuildAuthOptions(opts).AllowReauth
+	return opts
+}
+
+// cloudDomain returns the explicit domain of the user or project, falling
+// back to domain_id/domain_name and then to default_domain
+func cloudDomain(id, name string, auth *clouds.AuthInfo) (string, string) {
+	if id != "" || name != "" {
+		return id, name
+	}
+	if auth.DomainID != "" || auth.DomainName != "" {
+		return auth.DomainID, auth.DomainName
+	}
+	return auth.DefaultDomain, ""
+}
+
+// configDirs returns the directories searched for clouds.yaml
+func configDirs() []string {
+	dirs := []string{"."}
+	if dir, err := os.UserConfigDir(); err == nil {
+		dirs = append(dirs, filepath.Join(dir, "openstack"))
+	}
+	return append(dirs, "/etc/openstack")
+}
+
+// findConfigFile returns the first <name>.yaml or <name>.yml found in the
+// config directories
+func findConfigFile(name string) string {
+	for _, dir := range configDirs() {
+		for _, ext := range []string{".yaml", ".yml"} {
+			path := filepath.Join(dir, name+ext)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return path
+			}
+		}
+	}
+	return ""
+}
+
+// readYAMLFile decodes a YAML file into generic maps
+func readYAMLFile(path string) (map[interface{}]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	doc := map[interface{}]interface{}{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return doc, nil
+}
+
+// mergeYAML merges override into base recursively, as the SDK merges
+// secure.yaml into clouds.yaml
+func mergeYAML(base, override map[interface{}]interface{}) map[interface{}]interface{} {
+	merged := make(map[interface{}]interface{}, len(base))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		baseMap, baseOK := merged[k].(map[interface{}]interface{})
+		overrideMap, overrideOK := v.(map[interface{}]interface{})
+		if baseOK && overrideOK {
+			merged[k] = mergeYAML(baseMap, overrideMap)
+		} else {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+// firstNonEmpty returns the first non-empty string
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
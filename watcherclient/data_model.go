@@ -1,7 +1,17 @@
 package watcherclient
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"reflect"
+	"strings"
+)
+
+// Data model types supported by Watcher
+const (
+	DataModelTypeCompute = "compute"
+	DataModelTypeStorage = "storage"
 )
 
 // GetDataModel retrieves the infrastructure data model
@@ -23,3 +33,323 @@ func (c *Client) GetDataModel(dataModelType string) (*DataModel, error) {
 
 	return &result, nil
 }
+
+// GetComputeDataModel retrieves and decodes the compute data model
+func (c *Client) GetComputeDataModel() (*ComputeDataModel, error) {
+	dm, err := c.GetDataModel(DataModelTypeCompute)
+	if err != nil {
+		return nil, err
+	}
+	return ParseComputeDataModel(dm)
+}
+
+// GetStorageDataModel retrieves and decodes the storage data model
+func (c *Client) GetStorageDataModel() (*StorageDataModel, error) {
+	dm, err := c.GetDataModel(DataModelTypeStorage)
+	if err != nil {
+		return nil, err
+	}
+	return ParseStorageDataModel(dm)
+}
+
+// ComputeNode represents a hypervisor in the compute data model
+type ComputeNode struct {
+	UUID             string                 `json:"uuid"`
+	Hostname         string                 `json:"hostname"`
+	State            string                 `json:"state"`  // up, down
+	Status           string                 `json:"status"` // enabled, disabled
+	DisabledReason   string                 `json:"disabled_reason,omitempty"`
+	AvailabilityZone string                 `json:"availability_zone,omitempty"`
+	VCPUs            int                    `json:"vcpus"`
+	VCPUReserved     int                    `json:"vcpu_reserved,omitempty"`
+	VCPURatio        float64                `json:"vcpu_ratio,omitempty"`
+	Memory           int                    `json:"memory"` // MB
+	MemoryMBReserved int                    `json:"memory_mb_reserved,omitempty"`
+	MemoryRatio      float64                `json:"memory_ratio,omitempty"`
+	Disk             int                    `json:"disk"` // GB
+	DiskGBReserved   int                    `json:"disk_gb_reserved,omitempty"`
+	DiskRatio        float64                `json:"disk_ratio,omitempty"`
+	Extra            map[string]interface{} `json:"-"` // Fields not known to this client
+}
+
+// Instance represents a server in the compute data model
+type Instance struct {
+	UUID           string                 `json:"uuid"`
+	Name           string                 `json:"name"`
+	State          string                 `json:"state"`
+	ProjectID      string                 `json:"project_id,omitempty"`
+	VCPUs          int                    `json:"vcpus"`
+	Memory         int                    `json:"memory"` // MB
+	Disk           int                    `json:"disk"`   // GB
+	Locked         bool                   `json:"locked,omitempty"`
+	WatcherExclude bool                   `json:"watcher_exclude,omitempty"`
+	Metadata       map[string]interface{} `json:"metadata,omitempty"`
+	NodeUUID       string                 `json:"-"` // Compute node hosting the instance
+	Host           string                 `json:"-"` // Hostname of the hosting compute node
+	Extra          map[string]interface{} `json:"-"` // Fields not known to this client
+}
+
+// ComputeDataModel is the typed form of the compute data model
+type ComputeDataModel struct {
+	Nodes     []ComputeNode
+	Instances []Instance
+}
+
+// Node returns the compute node matching the given UUID or hostname
+func (m *ComputeDataModel) Node(identifier string) (*ComputeNode, bool) {
+	for i := range m.Nodes {
+		if m.Nodes[i].UUID == identifier || m.Nodes[i].Hostname == identifier {
+			return &m.Nodes[i], true
+		}
+	}
+	return nil, false
+}
+
+// InstancesOn returns the instances placed on the given compute node
+func (m *ComputeDataModel) InstancesOn(nodeUUID string) []Instance {
+	var instances []Instance
+	for _, instance := range m.Instances {
+		if instance.NodeUUID == nodeUUID {
+			instances = append(instances, instance)
+		}
+	}
+	return instances
+}
+
+// StorageNode represents a cinder-volume host in the storage data model
+type StorageNode struct {
+	Host       string                 `json:"host"`
+	Zone       string                 `json:"zone,omitempty"`
+	Status     string                 `json:"status"`
+	State      string                 `json:"state"`
+	VolumeType []string               `json:"volume_type,omitempty"`
+	Extra      map[string]interface{} `json:"-"` // Fields not known to this client
+}
+
+// Pool represents a storage pool in the storage data model
+type Pool struct {
+	Name                  string                 `json:"name"`
+	TotalVolumes          int                    `json:"total_volumes"`
+	TotalCapacityGB       float64                `json:"total_capacity_gb"`
+	FreeCapacityGB        float64                `json:"free_capacity_gb"`
+	ProvisionedCapacityGB float64                `json:"provisioned_capacity_gb"`
+	AllocatedCapacityGB   float64                `json:"allocated_capacity_gb"`
+	VirtualFree           float64                `json:"virtual_free,omitempty"`
+	Host                  string                 `json:"-"` // Storage node owning the pool
+	Extra                 map[string]interface{} `json:"-"` // Fields not known to this client
+}
+
+// Volume represents a cinder volume in the storage data model
+type Volume struct {
+	UUID        string                   `json:"uuid"`
+	Name        string                   `json:"name,omitempty"`
+	Size        int                      `json:"size"` // GB
+	Status      string                   `json:"status"`
+	ProjectID   string                   `json:"project_id,omitempty"`
+	SnapshotID  string                   `json:"snapshot_id,omitempty"`
+	Bootable    bool                     `json:"bootable,omitempty"`
+	Multiattach bool                     `json:"multiattach,omitempty"`
+	Attachments []map[string]interface{} `json:"attachments,omitempty"`
+	Metadata    map[string]interface{}   `json:"metadata,omitempty"`
+	Pool        string                   `json:"-"` // Pool holding the volume
+	Extra       map[string]interface{}   `json:"-"` // Fields not known to this client
+}
+
+// StorageDataModel is the typed form of the storage data model
+type StorageDataModel struct {
+	Nodes   []StorageNode
+	Pools   []Pool
+	Volumes []Volume
+}
+
+// ParseComputeDataModel decodes the flattened compute data model. Watcher
+// returns one element per instance, prefixed with "node_" and "server_",
+// and one element per compute node without instances.
+func ParseComputeDataModel(dm *DataModel) (*ComputeDataModel, error) {
+	elements, err := dataModelElements(dm)
+	if err != nil {
+		return nil, err
+	}
+
+	model := &ComputeDataModel{}
+	seenNodes := map[string]bool{}
+
+	for i, element := range elements {
+		groups := splitPrefixed(element, "node_", "server_")
+
+		var node ComputeNode
+		if err := decodeElement(groups["node_"], &node, &node.Extra); err != nil {
+			return nil, fmt.Errorf("failed to decode compute node in element %d: %w", i, err)
+		}
+
+		key := node.UUID
+		if key == "" {
+			key = node.Hostname
+		}
+		if key != "" && !seenNodes[key] {
+			seenNodes[key] = true
+			model.Nodes = append(model.Nodes, node)
+		}
+
+		if len(groups["server_"]) == 0 {
+			continue
+		}
+
+		var instance Instance
+		if err := decodeElement(groups["server_"], &instance, &instance.Extra); err != nil {
+			return nil, fmt.Errorf("failed to decode instance in element %d: %w", i, err)
+		}
+		instance.NodeUUID = node.UUID
+		instance.Host = node.Hostname
+		model.Instances = append(model.Instances, instance)
+	}
+
+	return model, nil
+}
+
+// ParseStorageDataModel decodes the flattened storage data model. Elements
+// are prefixed with "node_", "pool_" and "volume_".
+func ParseStorageDataModel(dm *DataModel) (*StorageDataModel, error) {
+	elements, err := dataModelElements(dm)
+	if err != nil {
+		return nil, err
+	}
+
+	model := &StorageDataModel{}
+	seenNodes := map[string]bool{}
+	seenPools := map[string]bool{}
+
+	for i, element := range elements {
+		groups := splitPrefixed(element, "node_", "pool_", "volume_")
+
+		var node StorageNode
+		if err := decodeElement(groups["node_"], &node, &node.Extra); err != nil {
+			return nil, fmt.Errorf("failed to decode storage node in element %d: %w", i, err)
+		}
+		if node.Host != "" && !seenNodes[node.Host] {
+			seenNodes[node.Host] = true
+			model.Nodes = append(model.Nodes, node)
+		}
+
+		var pool Pool
+		if len(groups["pool_"]) > 0 {
+			if err := decodeElement(groups["pool_"], &pool, &pool.Extra); err != nil {
+				return nil, fmt.Errorf("failed to decode pool in element %d: %w", i, err)
+			}
+			pool.Host = node.Host
+			if pool.Name != "" && !seenPools[pool.Name] {
+				seenPools[pool.Name] = true
+				model.Pools = append(model.Pools, pool)
+			}
+		}
+
+		if len(groups["volume_"]) == 0 {
+			continue
+		}
+
+		var volume Volume
+		if err := decodeElement(groups["volume_"], &volume, &volume.Extra); err != nil {
+			return nil, fmt.Errorf("failed to decode volume in element %d: %w", i, err)
+		}
+		volume.Pool = pool.Name
+		model.Volumes = append(model.Volumes, volume)
+	}
+
+	return model, nil
+}
+
+// dataModelElements returns the flattened elements of a data model
+func dataModelElements(dm *DataModel) ([]map[string]interface{}, error) {
+	if dm == nil {
+		return nil, fmt.Errorf("data model cannot be nil")
+	}
+
+	if dm.Context != nil {
+		return dm.Context, nil
+	}
+
+	// Some deployments wrap the elements in the data field
+	raw, ok := dm.Data["context"]
+	if !ok {
+		return nil, nil
+	}
+
+	list, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected data model context type %T", raw)
+	}
+
+	elements := make([]map[string]interface{}, 0, len(list))
+	for _, item := range list {
+		element, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected data model element type %T", item)
+		}
+		elements = append(elements, element)
+	}
+
+	return elements, nil
+}
+
+// splitPrefixed groups the keys of element by prefix, stripping the prefix
+func splitPrefixed(element map[string]interface{}, prefixes ...string) map[string]map[string]interface{} {
+	groups := make(map[string]map[string]interface{}, len(prefixes))
+	for _, prefix := range prefixes {
+		groups[prefix] = map[string]interface{}{}
+	}
+
+	for key, value := range element {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(key, prefix) {
+				groups[prefix][strings.TrimPrefix(key, prefix)] = value
+				break
+			}
+		}
+	}
+
+	return groups
+}
+
+// decodeElement decodes fields into v and collects unknown fields into extra.
+// Null values are dropped so that they decode as zero values.
+func decodeElement(fields map[string]interface{}, v interface{}, extra *map[string]interface{}) error {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	known := jsonFieldNames(reflect.TypeOf(v).Elem())
+	clean := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		if value == nil {
+			continue
+		}
+		if !known[key] {
+			if *extra == nil {
+				*extra = map[string]interface{}{}
+			}
+			(*extra)[key] = value
+			continue
+		}
+		clean[key] = value
+	}
+
+	data, err := json.Marshal(clean)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// jsonFieldNames returns the JSON names of the exported fields of t
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+		if name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+	return names
+}
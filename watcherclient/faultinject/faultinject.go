@@ -0,0 +1,325 @@
+// Package faultinject provides an http.RoundTripper that injects failures
+// into Watcher client traffic, for testing automation under error
+// conditions.
+//
+// Example use:
+//
+//	transport := faultinject.New(faultinject.Options{
+//		Rules: []faultinject.Rule{
+//			{Path: "/v1/audits", Kind: faultinject.ServerError, Probability: 0.2},
+//			{Kind: faultinject.Unauthorized, Every: 10},
+//		},
+//	})
+//	transport.Install(client)
+package faultinject
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Kind is the kind of injected fault
+type Kind int
+
+const (
+	ServerError  Kind = iota // Respond with a 5xx status
+	Unauthorized             // Respond with 401, as for an expired token
+	Timeout                  // Fail with a timeout error
+	Truncate                 // Forward the request and cut the response body
+	Delay                    // Forward the request after a delay
+)
+
+// String returns the name of the fault kind
+func (k Kind) String() string {
+	switch k {
+	case ServerError:
+		return "server_error"
+	case Unauthorized:
+		return "unauthorized"
+	case Timeout:
+		return "timeout"
+	case Truncate:
+		return "truncate"
+	case Delay:
+		return "delay"
+	default:
+		return fmt.Sprintf("kind(%d)", int(k))
+	}
+}
+
+// Rule describes when and how to inject a fault. A rule without
+// Probability or Every applies to every matching request.
+type Rule struct {
+	Method      string        // Matches any method when empty
+	Path        string        // Matches request paths containing it, e.g. "/v1/audits"
+	Kind        Kind          // Fault to inject
+	Probability float64       // Chance of injecting on a matching request
+	Every       int           // Inject on every Nth matching request
+	Times       int           // Maximum number of injections, 0 is unlimited
+	StatusCode  int           // ServerError status, defaults to 503
+	Delay       time.Duration // Delay before responding for Delay and Timeout
+	KeepBytes   int           // Bytes kept by Truncate, defaults to half the body
+}
+
+// Options configures a Transport
+type Options struct {
+	Base  http.RoundTripper // Defaults to the transport replaced by Install
+	Rules []Rule
+	Seed  int64 // Seed of probabilistic rules, 0 uses the current time
+}
+
+// Injection records an injected fault
+type Injection struct {
+	Rule   int // Index of the rule
+	Kind   Kind
+	Method string
+	Path   string
+	Time   time.Time
+}
+
+// TimeoutError is returned for injected timeouts. It implements net.Error.
+type TimeoutError struct {
+	Method string
+	Path   string
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("injected timeout: %s %s", e.Method, e.Path)
+}
+
+// Timeout reports that the error is a timeout
+func (e *TimeoutError) Timeout() bool { return true }
+
+// Temporary reports that the error is temporary
+func (e *TimeoutError) Temporary() bool { return true }
+
+// Transporter is a client whose transport can be replaced, such as
+// *watcherclient.Client
+type Transporter interface {
+	GetTransport() http.RoundTripper
+	SetTransport(rt http.RoundTripper)
+}
+
+// Transport is an http.RoundTripper injecting faults
+type Transport struct {
+	base       http.RoundTripper
+	mutex      sync.Mutex
+	random     *rand.Rand
+	rules      []*ruleState
+	injections []Injection
+}
+
+// ruleState tracks the matches and injections of a rule
+type ruleState struct {
+	Rule
+	matched  int
+	injected int
+}
+
+// New creates a fault injecting transport
+func New(opts Options) *Transport {
+	if opts.Seed == 0 {
+		opts.Seed = time.Now().UnixNano()
+	}
+
+	t := &Transport{
+		base:   opts.Base,
+		random: rand.New(rand.NewSource(opts.Seed)),
+	}
+	for _, rule := range opts.Rules {
+		t.AddRule(rule)
+	}
+	return t
+}
+
+// Install makes client send its requests through the transport. Requests
+// without injected faults are forwarded to the transport of the client,
+// keeping its TLS and proxy settings, unless Options.Base is set.
+func (t *Transport) Install(client Transporter) {
+	t.mutex.Lock()
+	if t.base == nil {
+		t.base = client.GetTransport()
+	}
+	t.mutex.Unlock()
+	client.SetTransport(t)
+}
+
+// AddRule adds a rule evaluated after the existing ones
+func (t *Transport) AddRule(rule Rule) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if rule.StatusCode == 0 {
+		rule.StatusCode = http.StatusServiceUnavailable
+	}
+	t.rules = append(t.rules, &ruleState{Rule: rule})
+}
+
+// ClearRules removes every rule
+func (t *Transport) ClearRules() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.rules = nil
+}
+
+// Injections returns the faults injected so far
+func (t *Transport) Injections() []Injection {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return append([]Injection(nil), t.injections...)
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rule := t.pick(req)
+	if rule == nil {
+		return t.forward(req)
+	}
+
+	switch rule.Kind {
+	case ServerError:
+		closeBody(req)
+		return errorResponse(req, rule.StatusCode, "Injected server error"), nil
+
+	case Unauthorized:
+		closeBody(req)
+		return errorResponse(req, http.StatusUnauthorized, "The request you have made requires authentication."), nil
+
+	case Timeout:
+		closeBody(req)
+		if err := sleep(req, rule.Delay); err != nil {
+			return nil, err
+		}
+		return nil, &TimeoutError{Method: req.Method, Path: req.URL.Path}
+
+	case Truncate:
+		resp, err := t.forward(req)
+		if err != nil {
+			return nil, err
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		keep := rule.KeepBytes
+		if keep <= 0 || keep > len(body) {
+			keep = len(body) / 2
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body[:keep]))
+		resp.ContentLength = int64(keep)
+		resp.Header.Del("Content-Length")
+		return resp, nil
+
+	case Delay:
+		if err := sleep(req, rule.Delay); err != nil {
+			closeBody(req)
+			return nil, err
+		}
+		return t.forward(req)
+	}
+
+	return t.forward(req)
+}
+
+// forward sends the request through the base transport
+func (t *Transport) forward(req *http.Request) (*http.Response, error) {
+	t.mutex.Lock()
+	base := t.base
+	t.mutex.Unlock()
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
+
+// pick returns the rule injecting a fault into the request, if any
+func (t *Transport) pick(req *http.Request) *ruleState {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	for i, rule := range t.rules {
+		if rule.Method != "" && rule.Method != req.Method {
+			continue
+		}
+		if !strings.Contains(req.URL.Path, rule.Path) {
+			continue
+		}
+		if rule.Times > 0 && rule.injected >= rule.Times {
+			continue
+		}
+
+		rule.matched++
+		if rule.Every > 0 && rule.matched%rule.Every != 0 {
+			continue
+		}
+		if rule.Probability > 0 && t.random.Float64() >= rule.Probability {
+			continue
+		}
+
+		rule.injected++
+		t.injections = append(t.injections, Injection{
+			Rule:   i,
+			Kind:   rule.Kind,
+			Method: req.Method,
+			Path:   req.URL.Path,
+			Time:   time.Now(),
+		})
+		return rule
+	}
+
+	return nil
+}
+
+// errorResponse builds a response in the Watcher error format
+func errorResponse(req *http.Request, status int, message string) *http.Response {
+	faultString, _ := json.Marshal(map[string]interface{}{
+		"faultstring": message,
+		"faultcode":   "Server",
+		"debuginfo":   nil,
+	})
+	body, _ := json.Marshal(map[string]interface{}{
+		"error_message": string(faultString),
+	})
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// sleep waits for d or until the request is cancelled
+func sleep(req *http.Request, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-req.Context().Done():
+		return req.Context().Err()
+	}
+}
+
+// closeBody closes the body of a request that is not forwarded
+func closeBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
@@ -0,0 +1,140 @@
+package faultinject
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+	"github.com/overwatch144/golang-watcherclient/watcherclient/watchertest"
+)
+
+// Test 1: Deterministic server errors and mid-session 401s
+func TestServerErrorAndReauth(t *testing.T) {
+	server := watchertest.NewServer(watchertest.Options{})
+	defer server.Close()
+
+	client, err := watcherclient.NewClient(server.ClientOptions())
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	transport := New(Options{Rules: []Rule{
+		{Method: http.MethodGet, Path: "/v1/goals", Kind: ServerError, Every: 2},
+		{Path: "/v1/strategies", Kind: Unauthorized, Times: 1},
+	}})
+	transport.Install(client)
+
+	if _, err := client.ListGoals(nil); err != nil {
+		t.Errorf("Expected first request to succeed, got %v", err)
+	}
+	_, err = client.ListGoals(nil)
+	if apiErr, ok := err.(*watcherclient.APIError); !ok || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected injected 503, got %v", err)
+	}
+
+	// The client re-authenticates and retries after the injected 401
+	if _, err := client.ListStrategies(nil); err != nil {
+		t.Errorf("Expected re-authentication to recover, got %v", err)
+	}
+
+	injections := transport.Injections()
+	if len(injections) != 2 || injections[1].Kind != Unauthorized {
+		t.Errorf("Expected 2 injections, got %+v", injections)
+	}
+}
+
+// Test 2: Timeouts, truncation and delays
+func TestTimeoutTruncateDelay(t *testing.T) {
+	server := watchertest.NewServer(watchertest.Options{})
+	defer server.Close()
+	client := server.Client()
+
+	transport := New(Options{Rules: []Rule{{Path: "/v1/goals", Kind: Timeout}}})
+	transport.Install(client)
+
+	_, err := client.ListGoals(nil)
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Errorf("Expected timeout error, got %v", err)
+	}
+
+	transport.ClearRules()
+	transport.AddRule(Rule{Path: "/v1/goals", Kind: Truncate})
+	if _, err := client.ListGoals(nil); err == nil || !strings.Contains(err.Error(), "failed to parse response") {
+		t.Errorf("Expected parse error for truncated body, got %v", err)
+	}
+
+	transport.ClearRules()
+	transport.AddRule(Rule{Kind: Delay, Delay: 50 * time.Millisecond})
+	start := time.Now()
+	if _, err := client.ListGoals(nil); err != nil {
+		t.Errorf("Expected delayed request to succeed, got %v", err)
+	}
+	if time.Since(start) < 50*time.Millisecond {
+		t.Error("Expected request to be delayed")
+	}
+}
+
+// Test 3: Probabilistic rules are reproducible with a seed
+func TestProbability(t *testing.T) {
+	count := func() int {
+		transport := New(Options{
+			Base: roundTripFunc(func(*http.Request) (*http.Response, error) {
+				return &http.Response{StatusCode: 200, Body: http.NoBody}, nil
+			}),
+			Seed:  42,
+			Rules: []Rule{{Kind: ServerError, Probability: 0.5}},
+		})
+		for i := 0; i < 100; i++ {
+			req, _ := http.NewRequest(http.MethodGet, "http://watcher/v1/audits", nil)
+			transport.RoundTrip(req)
+		}
+		return len(transport.Injections())
+	}
+
+	first := count()
+	if first == 0 || first == 100 {
+		t.Errorf("Expected some injections, got %d", first)
+	}
+	if second := count(); second != first {
+		t.Errorf("Expected %d injections with the same seed, got %d", first, second)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// Test 4: Installed transports forward through the client TLS settings
+func TestInstallKeepsClientTransport(t *testing.T) {
+	server := watchertest.NewServer(watchertest.Options{TLS: true})
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, server.CACertPEM(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	opts := server.ClientOptions()
+	opts.CACert = caFile
+	client, err := watcherclient.NewClient(opts)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	transport := New(Options{Rules: []Rule{{Path: "/v1/audits", Kind: ServerError}}})
+	transport.Install(client)
+
+	if _, err := client.ListGoals(nil); err != nil {
+		t.Errorf("Expected forwarded request to trust the CA, got %v", err)
+	}
+	if _, err := client.ListAudits(nil); err == nil {
+		t.Error("Expected injected error for audits")
+	}
+}
@@ -0,0 +1,89 @@
+package formatter
+
+import (
+	"reflect"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+)
+
+// Column maps an output label to a JSON field of a resource
+type Column struct {
+	Label string // Label used by python-watcherclient, e.g. "Audit Type"
+	Field string // JSON field name, e.g. "audit_type"
+}
+
+// ResourceColumns holds the columns of the list and show views of a
+// resource
+type ResourceColumns struct {
+	List []Column
+	Show []Column
+}
+
+// Common columns
+var (
+	uuidColumn      = Column{"UUID", "uuid"}
+	nameColumn      = Column{"Name", "name"}
+	createdAtColumn = Column{"Created At", "created_at"}
+	updatedAtColumn = Column{"Updated At", "updated_at"}
+	deletedAtColumn = Column{"Deleted At", "deleted_at"}
+)
+
+// Columns of the Watcher resources, following python-watcherclient
+var (
+	AuditColumns = ResourceColumns{
+		List: []Column{uuidColumn, nameColumn, {"Audit Type", "audit_type"}, {"State", "state"}, {"Goal", "goal"}, {"Strategy", "strategy"}, {"Auto Trigger", "auto_trigger"}},
+		Show: []Column{uuidColumn, nameColumn, createdAtColumn, updatedAtColumn, deletedAtColumn, {"State", "state"}, {"Audit Type", "audit_type"}, {"Parameters", "parameters"}, {"Interval", "interval"}, {"Goal", "goal"}, {"Strategy", "strategy"}, {"Audit Scope", "scope"}, {"Auto Trigger", "auto_trigger"}, {"Next Run Time", "next_run_time"}, {"Hostname", "hostname"}},
+	}
+
+	AuditTemplateColumns = ResourceColumns{
+		List: []Column{uuidColumn, nameColumn, {"Goal", "goal"}, {"Strategy", "strategy"}},
+		Show: []Column{uuidColumn, createdAtColumn, updatedAtColumn, deletedAtColumn, nameColumn, {"Description", "description"}, {"Goal", "goal"}, {"Strategy", "strategy"}, {"Audit Scope", "scope"}},
+	}
+
+	ActionPlanColumns = ResourceColumns{
+		List: []Column{uuidColumn, {"Audit", "audit_uuid"}, {"State", "state"}, updatedAtColumn, {"Global efficacy", "global_efficacy"}},
+		Show: []Column{uuidColumn, createdAtColumn, updatedAtColumn, deletedAtColumn, {"Audit", "audit_uuid"}, {"Strategy", "strategy"}, {"State", "state"}, {"Global efficacy", "global_efficacy"}, {"Hostname", "hostname"}},
+	}
+
+	ActionColumns = ResourceColumns{
+		List: []Column{uuidColumn, {"Parents", "parents"}, {"State", "state"}, {"Action Plan", "action_plan_uuid"}, {"Action", "action_type"}},
+		Show: []Column{uuidColumn, createdAtColumn, updatedAtColumn, deletedAtColumn, {"Parents", "parents"}, {"State", "state"}, {"Action Plan", "action_plan_uuid"}, {"Action", "action_type"}, {"Parameters", "parameters"}},
+	}
+
+	GoalColumns = ResourceColumns{
+		List: []Column{uuidColumn, nameColumn, {"Display name", "display_name"}},
+		Show: []Column{uuidColumn, createdAtColumn, updatedAtColumn, deletedAtColumn, nameColumn, {"Display name", "display_name"}, {"Efficacy specification", "efficacy_specification"}},
+	}
+
+	StrategyColumns = ResourceColumns{
+		List: []Column{uuidColumn, nameColumn, {"Display name", "display_name"}, {"Goal", "goal_uuid"}},
+		Show: []Column{uuidColumn, createdAtColumn, updatedAtColumn, deletedAtColumn, nameColumn, {"Display name", "display_name"}, {"Goal", "goal_uuid"}, {"Parameters spec", "parameters_spec"}},
+	}
+
+	ServiceColumns = ResourceColumns{
+		List: []Column{{"ID", "id"}, nameColumn, {"Host", "host"}, {"Status", "status"}},
+		Show: []Column{{"ID", "id"}, nameColumn, {"Host", "host"}, {"Status", "status"}, {"Last seen up", "last_seen_up"}},
+	}
+)
+
+// registry maps resource types to their columns
+var registry = map[reflect.Type]ResourceColumns{
+	reflect.TypeOf(watcherclient.Audit{}):         AuditColumns,
+	reflect.TypeOf(watcherclient.AuditTemplate{}): AuditTemplateColumns,
+	reflect.TypeOf(watcherclient.ActionPlan{}):    ActionPlanColumns,
+	reflect.TypeOf(watcherclient.Action{}):        ActionColumns,
+	reflect.TypeOf(watcherclient.Goal{}):          GoalColumns,
+	reflect.TypeOf(watcherclient.Strategy{}):      StrategyColumns,
+	reflect.TypeOf(watcherclient.Service{}):       ServiceColumns,
+}
+
+// ColumnsFor returns the columns registered for the type of v, which may
+// be a resource, a pointer to one or a slice of them
+func ColumnsFor(v interface{}) (ResourceColumns, bool) {
+	t := reflect.TypeOf(v)
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice) {
+		t = t.Elem()
+	}
+	columns, ok := registry[t]
+	return columns, ok
+}
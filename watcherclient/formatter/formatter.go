@@ -0,0 +1,391 @@
+// Package formatter renders Watcher resources as tables, JSON, YAML, CSV or
+// Go templates, using the column names of python-watcherclient.
+//
+// Example use:
+//
+//	audits, err := client.ListAudits(nil)
+//	err = formatter.Render(os.Stdout, audits, formatter.Options{
+//		Format:  formatter.FormatTable,
+//		Columns: []string{"UUID", "State"},
+//	})
+package formatter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Format is an output format
+type Format string
+
+const (
+	FormatTable    Format = "table"
+	FormatJSON     Format = "json"
+	FormatYAML     Format = "yaml"
+	FormatCSV      Format = "csv"
+	FormatTemplate Format = "template"
+)
+
+// Formats lists the supported formats
+var Formats = []Format{FormatTable, FormatJSON, FormatYAML, FormatCSV, FormatTemplate}
+
+// Options configures rendering
+type Options struct {
+	Format    Format   // Defaults to FormatTable
+	Columns   []string // Labels or JSON field names to include, defaults to all view columns
+	Template  string   // Go template executed for each resource with FormatTemplate
+	NoHeaders bool     // Omit the header of tables and CSV
+}
+
+// ParseFormat validates a format name
+func ParseFormat(name string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == name {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported format '%s'", name)
+}
+
+// Render writes v, a resource or a slice of resources, to w. Slices use
+// the list view of the resource and single resources the show view.
+func Render(w io.Writer, v interface{}, opts Options) error {
+	if opts.Format == "" {
+		opts.Format = FormatTable
+	}
+
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	list := value.Kind() == reflect.Slice || value.Kind() == reflect.Array
+
+	if opts.Format == FormatTemplate {
+		return renderTemplate(w, value, list, opts.Template)
+	}
+
+	var rows []map[string]interface{}
+	if list {
+		if err := convert(v, &rows); err != nil {
+			return err
+		}
+	} else {
+		var row map[string]interface{}
+		if err := convert(v, &row); err != nil {
+			return err
+		}
+		rows = []map[string]interface{}{row}
+	}
+
+	columns, err := selectColumns(v, rows, list, opts.Columns)
+	if err != nil {
+		return err
+	}
+
+	switch opts.Format {
+	case FormatTable:
+		if list {
+			return renderTable(w, listTable(columns, rows), opts.NoHeaders)
+		}
+		return renderTable(w, showTable(columns, rows[0]), opts.NoHeaders)
+	case FormatJSON:
+		return renderJSON(w, columns, rows, list)
+	case FormatYAML:
+		return renderYAML(w, columns, rows, list)
+	case FormatCSV:
+		return renderCSV(w, columns, rows, opts.NoHeaders)
+	default:
+		return fmt.Errorf("unsupported format '%s'", opts.Format)
+	}
+}
+
+// selectColumns returns the columns to render
+func selectColumns(v interface{}, rows []map[string]interface{}, list bool, selected []string) ([]Column, error) {
+	var available []Column
+	if registered, ok := ColumnsFor(v); ok {
+		available = registered.Show
+		if list {
+			available = registered.List
+		}
+		if len(selected) > 0 {
+			// Allow selecting show columns in the list view
+			available = append(append([]Column(nil), available...), registered.Show...)
+		}
+	} else {
+		available = fieldColumns(rows)
+	}
+
+	if len(selected) == 0 {
+		return available, nil
+	}
+
+	var columns []Column
+	for _, name := range selected {
+		column, ok := findColumn(available, name)
+		if !ok {
+			if _, registered := ColumnsFor(v); registered {
+				return nil, fmt.Errorf("unknown column '%s'", name)
+			}
+			// Unregistered values, e.g. data model elements, accept any field
+			column = Column{Label: name, Field: name}
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// findColumn looks a column up by label or field, ignoring case
+func findColumn(columns []Column, name string) (Column, bool) {
+	for _, c := range columns {
+		if strings.EqualFold(c.Label, name) || strings.EqualFold(c.Field, name) {
+			return c, true
+		}
+	}
+	return Column{}, false
+}
+
+// fieldColumns derives sorted columns from the fields of untyped rows
+func fieldColumns(rows []map[string]interface{}) []Column {
+	seen := map[string]bool{}
+	for _, row := range rows {
+		for field := range row {
+			seen[field] = true
+		}
+	}
+
+	fields := make([]string, 0, len(seen))
+	for field := range seen {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	columns := make([]Column, len(fields))
+	for i, field := range fields {
+		columns[i] = Column{Label: field, Field: field}
+	}
+	return columns
+}
+
+// listTable returns the header and cells of a list view
+func listTable(columns []Column, rows []map[string]interface{}) [][]string {
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = c.Label
+	}
+
+	table := [][]string{header}
+	for _, row := range rows {
+		cells := make([]string, len(columns))
+		for i, c := range columns {
+			cells[i] = Cell(row[c.Field])
+		}
+		table = append(table, cells)
+	}
+	return table
+}
+
+// showTable returns the Field/Value table of a show view
+func showTable(columns []Column, row map[string]interface{}) [][]string {
+	table := [][]string{{"Field", "Value"}}
+	for _, c := range columns {
+		table = append(table, []string{c.Label, Cell(row[c.Field])})
+	}
+	return table
+}
+
+// renderTable writes a bordered table; the first row is the header
+func renderTable(w io.Writer, table [][]string, noHeaders bool) error {
+	if noHeaders {
+		table = table[1:]
+	}
+	if len(table) == 0 {
+		return nil
+	}
+
+	widths := make([]int, len(table[0]))
+	for _, row := range table {
+		for i, cell := range row {
+			if n := utf8.RuneCountInString(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	var b strings.Builder
+	border := func() {
+		for _, width := range widths {
+			b.WriteString("+" + strings.Repeat("-", width+2))
+		}
+		b.WriteString("+\n")
+	}
+	line := func(row []string) {
+		for i, cell := range row {
+			b.WriteString("| " + cell + strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)) + " ")
+		}
+		b.WriteString("|\n")
+	}
+
+	border()
+	for i, row := range table {
+		line(row)
+		if i == 0 && !noHeaders {
+			border()
+		}
+	}
+	border()
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// renderJSON writes objects keyed by column label
+func renderJSON(w io.Writer, columns []Column, rows []map[string]interface{}, list bool) error {
+	objects := make([]json.RawMessage, len(rows))
+	for i, row := range rows {
+		var b bytes.Buffer
+		b.WriteString("{")
+		for j, c := range columns {
+			if j > 0 {
+				b.WriteString(",")
+			}
+			key, _ := json.Marshal(c.Label)
+			value, err := json.Marshal(row[c.Field])
+			if err != nil {
+				return fmt.Errorf("failed to encode output: %w", err)
+			}
+			b.Write(key)
+			b.WriteString(":")
+			b.Write(value)
+		}
+		b.WriteString("}")
+		objects[i] = b.Bytes()
+	}
+
+	var out interface{} = objects
+	if !list {
+		out = objects[0]
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// renderYAML writes objects keyed by column label, in column order
+func renderYAML(w io.Writer, columns []Column, rows []map[string]interface{}, list bool) error {
+	objects := make([]yaml.MapSlice, len(rows))
+	for i, row := range rows {
+		for _, c := range columns {
+			objects[i] = append(objects[i], yaml.MapItem{Key: c.Label, Value: row[c.Field]})
+		}
+	}
+
+	var out interface{} = objects
+	if !list {
+		out = objects[0]
+	}
+
+	data, err := yaml.Marshal(out)
+	if err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// renderCSV writes one record per resource
+func renderCSV(w io.Writer, columns []Column, rows []map[string]interface{}, noHeaders bool) error {
+	cw := csv.NewWriter(w)
+	table := listTable(columns, rows)
+	if noHeaders {
+		table = table[1:]
+	}
+	if err := cw.WriteAll(table); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// renderTemplate executes a Go template for each resource
+func renderTemplate(w io.Writer, value reflect.Value, list bool, text string) error {
+	if text == "" {
+		return fmt.Errorf("a template is required with the template format")
+	}
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+
+	tmpl, err := template.New("output").Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+		"join": strings.Join,
+	}).Parse(text)
+	if err != nil {
+		return fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	if !list {
+		return tmpl.Execute(w, value.Interface())
+	}
+	for i := 0; i < value.Len(); i++ {
+		if err := tmpl.Execute(w, value.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// convert converts v to out through JSON
+func convert(v, out interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to convert output: %w", err)
+	}
+	return nil
+}
+
+// Cell formats a value for a table cell or CSV field
+func Cell(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case float64, bool:
+		return fmt.Sprint(value)
+	case []interface{}:
+		if len(value) == 0 {
+			return ""
+		}
+		parts := make([]string, len(value))
+		for i, item := range value {
+			if s, ok := item.(string); ok {
+				parts[i] = s
+			} else {
+				parts[i] = Cell(item)
+			}
+		}
+		return strings.Join(parts, ", ")
+	default:
+		data, _ := json.Marshal(value)
+		return string(data)
+	}
+}
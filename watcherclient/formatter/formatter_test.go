@@ -0,0 +1,101 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+)
+
+var testAudits = []watcherclient.Audit{
+	{UUID: "a1", Name: "nightly", AuditType: "ONESHOT", State: "SUCCEEDED", Goal: "dummy", AutoTrigger: true},
+	{UUID: "a2", Name: "continuous", AuditType: "CONTINUOUS", State: "ONGOING", Goal: "server_consolidation", Strategy: "basic"},
+}
+
+// Test 1: List and show tables
+func TestTable(t *testing.T) {
+	var b bytes.Buffer
+	if err := Render(&b, testAudits, Options{Columns: []string{"uuid", "Audit Type", "State"}}); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := "" +
+		"+------+------------+-----------+\n" +
+		"| UUID | Audit Type | State     |\n" +
+		"+------+------------+-----------+\n" +
+		"| a1   | ONESHOT    | SUCCEEDED |\n" +
+		"| a2   | CONTINUOUS | ONGOING   |\n" +
+		"+------+------------+-----------+\n"
+	if b.String() != expected {
+		t.Errorf("Expected table:\n%s\ngot:\n%s", expected, b.String())
+	}
+
+	b.Reset()
+	if err := Render(&b, &testAudits[0], Options{}); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if !strings.Contains(b.String(), "| Auto Trigger  | true ") || !strings.HasPrefix(b.String(), "+---") {
+		t.Errorf("Expected show table, got:\n%s", b.String())
+	}
+
+	if err := Render(&b, testAudits, Options{Columns: []string{"Unknown"}}); err == nil {
+		t.Error("Expected error for unknown column")
+	}
+}
+
+// Test 2: JSON, YAML and CSV use python-watcherclient labels
+func TestStructuredFormats(t *testing.T) {
+	var b bytes.Buffer
+	if err := Render(&b, testAudits, Options{Format: FormatJSON}); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	var objects []map[string]interface{}
+	if err := json.Unmarshal(b.Bytes(), &objects); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if len(objects) != 2 || objects[1]["Strategy"] != "basic" || objects[0]["Auto Trigger"] != true {
+		t.Errorf("Unexpected JSON %v", objects)
+	}
+
+	b.Reset()
+	if err := Render(&b, testAudits[0], Options{Format: FormatYAML, Columns: []string{"UUID", "Goal"}}); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if b.String() != "UUID: a1\nGoal: dummy\n" {
+		t.Errorf("Unexpected YAML %q", b.String())
+	}
+
+	b.Reset()
+	if err := Render(&b, testAudits, Options{Format: FormatCSV, Columns: []string{"UUID", "Name"}}); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if b.String() != "UUID,Name\na1,nightly\na2,continuous\n" {
+		t.Errorf("Unexpected CSV %q", b.String())
+	}
+}
+
+// Test 3: Templates and untyped values
+func TestTemplateAndUntyped(t *testing.T) {
+	var b bytes.Buffer
+	if err := Render(&b, testAudits, Options{Format: FormatTemplate, Template: "{{.UUID}} {{.State}}"}); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if b.String() != "a1 SUCCEEDED\na2 ONGOING\n" {
+		t.Errorf("Unexpected template output %q", b.String())
+	}
+
+	elements := []map[string]interface{}{{"server_uuid": "s1", "node_hostname": "compute-1"}}
+	b.Reset()
+	if err := Render(&b, elements, Options{Format: FormatCSV, Columns: []string{"server_uuid", "node_hostname"}}); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if b.String() != "server_uuid,node_hostname\ns1,compute-1\n" {
+		t.Errorf("Unexpected CSV %q", b.String())
+	}
+
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("Expected error for unsupported format")
+	}
+}
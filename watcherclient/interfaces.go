@@ -0,0 +1,82 @@
+package watcherclient
+
+// AuditsAPI groups the audit operations
+type AuditsAPI interface {
+	CreateAudit(audit *Audit) (*Audit, error)
+	GetAudit(uuid string) (*Audit, error)
+	ListAudits(opts *ListOptions) ([]Audit, error)
+	UpdateAudit(uuid string, updates map[string]interface{}) (*Audit, error)
+	DeleteAudit(uuid string) error
+	StartAudit(uuid string) (*Audit, error)
+}
+
+// AuditTemplatesAPI groups the audit template operations
+type AuditTemplatesAPI interface {
+	CreateAuditTemplate(template *AuditTemplate) (*AuditTemplate, error)
+	GetAuditTemplate(uuid string) (*AuditTemplate, error)
+	ListAuditTemplates(opts *ListOptions) ([]AuditTemplate, error)
+	UpdateAuditTemplate(uuid string, updates map[string]interface{}) (*AuditTemplate, error)
+	DeleteAuditTemplate(uuid string) error
+}
+
+// ActionPlansAPI groups the action plan operations
+type ActionPlansAPI interface {
+	GetActionPlan(uuid string) (*ActionPlan, error)
+	ListActionPlans(opts *ListOptions) ([]ActionPlan, error)
+	UpdateActionPlan(uuid string, updates map[string]interface{}) (*ActionPlan, error)
+	DeleteActionPlan(uuid string) error
+	StartActionPlan(uuid string) (*ActionPlan, error)
+	CancelActionPlan(uuid string) (*ActionPlan, error)
+}
+
+// ActionsAPI groups the action operations
+type ActionsAPI interface {
+	GetAction(uuid string) (*Action, error)
+	ListActions(opts *ListOptions) ([]Action, error)
+	ListActionsByActionPlan(actionPlanUUID string) ([]Action, error)
+}
+
+// GoalsAPI groups the goal operations
+type GoalsAPI interface {
+	GetGoal(identifier string) (*Goal, error)
+	ListGoals(opts *ListOptions) ([]Goal, error)
+}
+
+// StrategiesAPI groups the strategy operations
+type StrategiesAPI interface {
+	GetStrategy(identifier string) (*Strategy, error)
+	ListStrategies(opts *ListOptions) ([]Strategy, error)
+	ListStrategiesByGoal(goalIdentifier string) ([]Strategy, error)
+}
+
+// ServicesAPI groups the service operations
+type ServicesAPI interface {
+	GetService(identifier string) (*Service, error)
+	ListServices(opts *ListOptions) ([]Service, error)
+}
+
+// DataModelAPI groups the data model operations
+type DataModelAPI interface {
+	GetDataModel(dataModelType string) (*DataModel, error)
+	GetComputeDataModel() (*ComputeDataModel, error)
+	GetStorageDataModel() (*StorageDataModel, error)
+}
+
+// API is the full set of Watcher operations implemented by Client. Code
+// depending on it, or on one of the per-resource interfaces, can be tested
+// with the watchermock package instead of an HTTP server.
+type API interface {
+	AuditsAPI
+	AuditTemplatesAPI
+	ActionPlansAPI
+	ActionsAPI
+	GoalsAPI
+	StrategiesAPI
+	ServicesAPI
+	DataModelAPI
+
+	Ping() error
+	GetVersion() (map[string]interface{}, error)
+}
+
+var _ API = (*Client)(nil)
@@ -0,0 +1,155 @@
+package watcherclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Headers whose values are never logged
+var sensitiveHeaders = []string{
+	"X-Auth-Token", "X-Subject-Token", "X-Service-Token",
+	"Authorization", "Cookie", "Set-Cookie",
+}
+
+// JSON fields whose values are never logged, matched as substrings of the
+// lower-cased key
+var sensitiveFields = []string{"password", "secret", "token", "passcode", "credential"}
+
+// requestIDHeader is the OpenStack request ID returned by Watcher
+const requestIDHeader = "X-Openstack-Request-Id"
+
+// logRequest logs a Watcher request: at debug level when it succeeded, info
+// for client errors and warning for server and transport errors
+func (c *Client) logRequest(req *http.Request, path string, resp *http.Response, body []byte, start time.Time, attempt int, err error) {
+	if c.logger == nil {
+		return
+	}
+
+	path, query, _ := strings.Cut(path, "?")
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("path", path),
+		slog.String("query", query),
+		slog.Duration("duration", time.Since(start)),
+		slog.Int("attempt", attempt),
+	}
+
+	level := slog.LevelDebug
+	switch {
+	case err != nil:
+		level = slog.LevelWarn
+		attrs = append(attrs, slog.String("error", err.Error()))
+	case resp.StatusCode >= 500:
+		level = slog.LevelWarn
+	case resp.StatusCode >= 400:
+		level = slog.LevelInfo
+	}
+	if resp != nil {
+		attrs = append(attrs,
+			slog.Int("status", resp.StatusCode),
+			slog.String("request_id", resp.Header.Get(requestIDHeader)))
+	}
+
+	if c.logBodies {
+		attrs = append(attrs, slog.Any("request_headers", redactHeaders(req.Header)))
+		if len(body) > 0 {
+			attrs = append(attrs, slog.String("request_body", redactBody(body)))
+		}
+		if resp != nil && resp.Body != nil {
+			// The body is read here and restored for the caller
+			data, readErr := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			resp.Body = io.NopCloser(bytes.NewReader(data))
+			if readErr == nil && len(data) > 0 {
+				attrs = append(attrs, slog.String("response_body", redactBody(data)))
+			}
+		}
+	}
+
+	c.logger.LogAttrs(context.Background(), level, "watcher request", attrs...)
+}
+
+// logInfo logs an event of the client at info level
+func (c *Client) logInfo(msg string, attrs ...slog.Attr) {
+	if c.logger != nil {
+		c.logger.LogAttrs(context.Background(), slog.LevelInfo, msg, attrs...)
+	}
+}
+
+// log logs an authentication event
+func (a *Authenticator) log(level slog.Level, msg string, attrs ...slog.Attr) {
+	if a.authOptions.Logger != nil {
+		a.authOptions.Logger.LogAttrs(context.Background(), level, msg, attrs...)
+	}
+}
+
+// redactHeaders returns a copy of headers with sensitive values replaced
+// by a hash prefix, which still allows telling tokens apart
+func redactHeaders(headers http.Header) http.Header {
+	redacted := headers.Clone()
+	for _, name := range sensitiveHeaders {
+		values := redacted.Values(name)
+		for i, value := range values {
+			values[i] = redactValue(value)
+		}
+	}
+	return redacted
+}
+
+// redactValue returns the hash prefix of a secret
+func redactValue(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return "{SHA256}" + hex.EncodeToString(sum[:8])
+}
+
+// redactBody returns a JSON body with sensitive fields redacted. Bodies
+// that are not JSON are omitted.
+func redactBody(body []byte) string {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return "<non-JSON body omitted>"
+	}
+	data, err := json.Marshal(redactJSON(v))
+	if err != nil {
+		return "<body omitted>"
+	}
+	return string(data)
+}
+
+// redactJSON replaces the values of sensitive fields
+func redactJSON(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for key, item := range value {
+			if isSensitiveField(key) {
+				value[key] = "***"
+			} else {
+				value[key] = redactJSON(item)
+			}
+		}
+	case []interface{}:
+		for i, item := range value {
+			value[i] = redactJSON(item)
+		}
+	}
+	return v
+}
+
+// isSensitiveField reports whether a JSON key holds a secret
+func isSensitiveField(key string) bool {
+	key = strings.ToLower(key)
+	for _, field := range sensitiveFields {
+		if strings.Contains(key, field) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package watcherclient_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+	"github.com/overwatch144/golang-watcherclient/watcherclient/watchertest"
+)
+
+// Test 1: Structured logs of requests and re-authentication, without secrets
+func TestLogging(t *testing.T) {
+	server := watchertest.NewServer(watchertest.Options{Username: "admin", Password: "secret"})
+	defer server.Close()
+
+	var logs bytes.Buffer
+	opts := server.ClientOptions()
+	opts.Logger = slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	opts.LogBodies = true
+
+	client, err := watcherclient.NewClient(opts)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	if _, err := client.CreateAuditTemplate(&watcherclient.AuditTemplate{Name: "nightly", Goal: "dummy"}); err != nil {
+		t.Fatalf("CreateAuditTemplate failed: %v", err)
+	}
+	server.RevokeTokens()
+	if _, err := client.ListGoals(nil); err != nil {
+		t.Fatalf("ListGoals failed: %v", err)
+	}
+
+	var messages []string
+	var retried bool
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Invalid log line %q: %v", line, err)
+		}
+		messages = append(messages, entry["msg"].(string))
+		if entry["msg"] == "watcher request" && entry["path"] == "/goals" && entry["attempt"] == float64(2) {
+			retried = true
+			if entry["status"] != float64(200) || entry["level"] != "DEBUG" {
+				t.Errorf("Expected successful retry at debug level, got %v", entry)
+			}
+		}
+	}
+
+	joined := strings.Join(messages, ",")
+	for _, want := range []string{"authenticated", "watcher request", "re-authenticating after 401", "token refreshed"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("Expected %q in logs, got %s", want, joined)
+		}
+	}
+	if !retried {
+		t.Error("Expected a logged retry of /goals")
+	}
+	if !strings.Contains(logs.String(), "nightly") {
+		t.Error("Expected the request body in the logs")
+	}
+	if strings.Contains(logs.String(), "gAAAA") || strings.Contains(logs.String(), "secret") {
+		t.Errorf("Expected tokens and passwords to be redacted, got %s", logs.String())
+	}
+}
@@ -0,0 +1,241 @@
+// Package multicloud runs Watcher operations on several regions, each with
+// its own Watcher endpoint, in parallel. Results are tagged with their
+// region and merged; failing regions are reported without discarding the
+// results of the others.
+//
+// Example use:
+//
+//	clouds := multicloud.New(map[string]watcherclient.API{
+//		"RegionOne": clientOne,
+//		"RegionTwo": clientTwo,
+//	})
+//	audits, err := clouds.ListAudits(nil)
+//	var partial *multicloud.PartialError
+//	if errors.As(err, &partial) {
+//		// audits holds the results of the regions that answered
+//	}
+package multicloud
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+)
+
+// Region is a named Watcher deployment
+type Region struct {
+	Name   string
+	Client watcherclient.API
+}
+
+// Clients is a facade over the clients of several regions
+type Clients struct {
+	regions []Region
+
+	// Concurrency limits the regions queried at once, 0 queries all
+	Concurrency int
+}
+
+// Tagged is a result and the region it comes from
+type Tagged[T any] struct {
+	Region string
+	Item   T
+}
+
+// RegionError is the failure of an operation in one region
+type RegionError struct {
+	Region string
+	Err    error
+}
+
+func (e *RegionError) Error() string {
+	return fmt.Sprintf("region %s: %v", e.Region, e.Err)
+}
+
+func (e *RegionError) Unwrap() error {
+	return e.Err
+}
+
+// PartialError reports the regions that failed while others succeeded, or
+// all of them
+type PartialError struct {
+	Errors    []*RegionError
+	Succeeded int // Number of regions that answered
+}
+
+func (e *PartialError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("%d of %d regions failed: %s",
+		len(e.Errors), len(e.Errors)+e.Succeeded, strings.Join(messages, "; "))
+}
+
+// Unwrap returns the region errors, for errors.Is and errors.As
+func (e *PartialError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Regions returns the names of the failed regions
+func (e *PartialError) Regions() []string {
+	names := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		names[i] = err.Region
+	}
+	return names
+}
+
+// New creates a facade over clients keyed by region name
+func New(clients map[string]watcherclient.API) *Clients {
+	c := &Clients{}
+	for name, client := range clients {
+		c.regions = append(c.regions, Region{Name: name, Client: client})
+	}
+	sort.Slice(c.regions, func(i, j int) bool { return c.regions[i].Name < c.regions[j].Name })
+	return c
+}
+
+// NewFromRegions creates a facade over regions, kept in the given order
+func NewFromRegions(regions ...Region) *Clients {
+	return &Clients{regions: append([]Region(nil), regions...)}
+}
+
+// Regions returns the region names
+func (c *Clients) Regions() []string {
+	names := make([]string, len(c.regions))
+	for i, r := range c.regions {
+		names[i] = r.Name
+	}
+	return names
+}
+
+// Client returns the client of a region
+func (c *Clients) Client(region string) (watcherclient.API, error) {
+	for _, r := range c.regions {
+		if r.Name == region {
+			return r.Client, nil
+		}
+	}
+	return nil, fmt.Errorf("region '%s' not found", region)
+}
+
+// List runs fn in every region and merges the tagged results. Results are
+// ordered by less, or by region when less is nil. A *PartialError is
+// returned with the results of the other regions when some regions fail.
+func List[T any](c *Clients, fn func(client watcherclient.API) ([]T, error), less func(a, b Tagged[T]) bool) ([]Tagged[T], error) {
+	lists := make([][]T, len(c.regions))
+	errs := c.run(func(i int, client watcherclient.API) error {
+		items, err := fn(client)
+		lists[i] = items
+		return err
+	})
+
+	var merged []Tagged[T]
+	for i, items := range lists {
+		for _, item := range items {
+			merged = append(merged, Tagged[T]{Region: c.regions[i].Name, Item: item})
+		}
+	}
+	if less != nil {
+		sort.SliceStable(merged, func(i, j int) bool { return less(merged[i], merged[j]) })
+	}
+
+	return merged, c.partial(errs)
+}
+
+// Get runs fn in every region and returns the results of the regions where
+// the resource exists. Not found errors are not failures.
+func Get[T any](c *Clients, fn func(client watcherclient.API) (T, error)) ([]Tagged[T], error) {
+	items := make([]*T, len(c.regions))
+	errs := c.run(func(i int, client watcherclient.API) error {
+		item, err := fn(client)
+		if watcherclient.IsNotFound(err) {
+			return nil
+		}
+		if err == nil {
+			items[i] = &item
+		}
+		return err
+	})
+
+	var found []Tagged[T]
+	for i, item := range items {
+		if item != nil {
+			found = append(found, Tagged[T]{Region: c.regions[i].Name, Item: *item})
+		}
+	}
+	return found, c.partial(errs)
+}
+
+// run calls fn for every region in parallel and returns the errors by
+// region index
+func (c *Clients) run(fn func(i int, client watcherclient.API) error) []error {
+	errs := make([]error, len(c.regions))
+
+	limit := c.Concurrency
+	if limit <= 0 || limit > len(c.regions) {
+		limit = len(c.regions)
+	}
+	slots := make(chan struct{}, limit)
+
+	var wg sync.WaitGroup
+	for i, r := range c.regions {
+		wg.Add(1)
+		go func(i int, client watcherclient.API) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			errs[i] = fn(i, client)
+		}(i, r.Client)
+	}
+	wg.Wait()
+
+	return errs
+}
+
+// partial builds the error reporting failed regions, nil if none failed
+func (c *Clients) partial(errs []error) error {
+	result := &PartialError{}
+	for i, err := range errs {
+		if err != nil {
+			result.Errors = append(result.Errors, &RegionError{Region: c.regions[i].Name, Err: err})
+		} else {
+			result.Succeeded++
+		}
+	}
+	if len(result.Errors) == 0 {
+		return nil
+	}
+	return result
+}
+
+// newestFirst orders resources by creation time, most recent first, then
+// by region
+func newestFirst[T any](createdAt func(T) string) func(a, b Tagged[T]) bool {
+	return func(a, b Tagged[T]) bool {
+		ta, tb := createdAt(a.Item), createdAt(b.Item)
+		if ta != tb {
+			return ta > tb
+		}
+		return a.Region < b.Region
+	}
+}
+
+// byName orders resources by name, then by region
+func byName[T any](name func(T) string) func(a, b Tagged[T]) bool {
+	return func(a, b Tagged[T]) bool {
+		na, nb := name(a.Item), name(b.Item)
+		if na != nb {
+			return na < nb
+		}
+		return a.Region < b.Region
+	}
+}
@@ -0,0 +1,106 @@
+package multicloud
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+	"github.com/overwatch144/golang-watcherclient/watcherclient/watchermock"
+)
+
+// Test 1: Merged and sorted results with a failing region
+func TestListAuditsPartialFailure(t *testing.T) {
+	one := &watchermock.Mock{}
+	one.On("ListAudits", watchermock.Any).Return([]watcherclient.Audit{
+		{UUID: "a1", CreatedAt: "2026-01-01T10:00:00"},
+		{UUID: "a3", CreatedAt: "2026-01-03T10:00:00"},
+	}, nil)
+	two := &watchermock.Mock{}
+	two.On("ListAudits", watchermock.Any).Return([]watcherclient.Audit{
+		{UUID: "a2", CreatedAt: "2026-01-02T10:00:00"},
+	}, nil)
+	three := &watchermock.Mock{}
+	three.On("ListAudits", watchermock.Any).Return(nil, &watcherclient.APIError{StatusCode: 503})
+
+	clouds := New(map[string]watcherclient.API{"RegionOne": one, "RegionTwo": two, "RegionThree": three})
+	clouds.Concurrency = 2
+
+	audits, err := clouds.ListAudits(nil)
+
+	var partial *PartialError
+	if !errors.As(err, &partial) {
+		t.Fatalf("Expected PartialError, got %v", err)
+	}
+	if partial.Succeeded != 2 || len(partial.Errors) != 1 || partial.Regions()[0] != "RegionThree" {
+		t.Errorf("Expected RegionThree to fail, got %v", err)
+	}
+	var apiErr *watcherclient.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 503 {
+		t.Errorf("Expected the API error to be unwrappable, got %v", err)
+	}
+
+	if len(audits) != 3 {
+		t.Fatalf("Expected 3 audits, got %d", len(audits))
+	}
+	want := []Tagged[string]{{"RegionOne", "a3"}, {"RegionTwo", "a2"}, {"RegionOne", "a1"}}
+	for i, w := range want {
+		if audits[i].Region != w.Region || audits[i].Item.UUID != w.Item {
+			t.Errorf("Expected %s/%s at %d, got %s/%s", w.Region, w.Item, i, audits[i].Region, audits[i].Item.UUID)
+		}
+	}
+}
+
+// Test 2: Get ignores regions where the resource does not exist
+func TestGetAudit(t *testing.T) {
+	one := &watchermock.Mock{}
+	one.On("GetAudit", "a1").Return(&watcherclient.Audit{UUID: "a1"}, nil)
+	two := &watchermock.Mock{}
+	two.On("GetAudit", "a1").Return(nil, &watcherclient.APIError{StatusCode: 404})
+
+	clouds := NewFromRegions(Region{Name: "RegionTwo", Client: two}, Region{Name: "RegionOne", Client: one})
+
+	found, err := clouds.GetAudit("a1")
+	if err != nil {
+		t.Fatalf("GetAudit failed: %v", err)
+	}
+	if len(found) != 1 || found[0].Region != "RegionOne" || found[0].Item.UUID != "a1" {
+		t.Errorf("Expected a1 in RegionOne, got %+v", found)
+	}
+
+	if names := clouds.Regions(); names[0] != "RegionTwo" {
+		t.Errorf("Expected regions in the given order, got %v", names)
+	}
+	if _, err := clouds.Client("RegionThree"); err == nil {
+		t.Error("Expected error for unknown region")
+	}
+}
+
+// Test 3: Generic List with a custom order
+func TestList(t *testing.T) {
+	one := &watchermock.Mock{}
+	one.On("ListGoals", watchermock.Any).Return([]watcherclient.Goal{{Name: "b"}}, nil)
+	two := &watchermock.Mock{}
+	two.On("ListGoals", watchermock.Any).Return([]watcherclient.Goal{{Name: "a"}}, nil)
+
+	clouds := New(map[string]watcherclient.API{"RegionOne": one, "RegionTwo": two})
+
+	names, err := List(clouds, func(client watcherclient.API) ([]string, error) {
+		goals, err := client.ListGoals(nil)
+		var names []string
+		for _, g := range goals {
+			names = append(names, g.Name)
+		}
+		return names, err
+	}, nil)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(names) != 2 || names[0].Region != "RegionOne" || names[0].Item != "b" {
+		t.Errorf("Expected region order without less, got %+v", names)
+	}
+
+	goals, err := clouds.ListGoals(nil)
+	if err != nil || goals[0].Item.Name != "a" {
+		t.Errorf("Expected goals by name, got %+v, %v", goals, err)
+	}
+}
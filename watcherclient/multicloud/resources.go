@@ -0,0 +1,82 @@
+package multicloud
+
+import (
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+)
+
+// ListAudits lists the audits of every region, most recent first
+func (c *Clients) ListAudits(opts *watcherclient.ListOptions) ([]Tagged[watcherclient.Audit], error) {
+	return List(c, func(client watcherclient.API) ([]watcherclient.Audit, error) {
+		return client.ListAudits(opts)
+	}, newestFirst(func(a watcherclient.Audit) string { return a.CreatedAt }))
+}
+
+// ListAuditTemplates lists the audit templates of every region, by name
+func (c *Clients) ListAuditTemplates(opts *watcherclient.ListOptions) ([]Tagged[watcherclient.AuditTemplate], error) {
+	return List(c, func(client watcherclient.API) ([]watcherclient.AuditTemplate, error) {
+		return client.ListAuditTemplates(opts)
+	}, byName(func(t watcherclient.AuditTemplate) string { return t.Name }))
+}
+
+// ListActionPlans lists the action plans of every region, most recent first
+func (c *Clients) ListActionPlans(opts *watcherclient.ListOptions) ([]Tagged[watcherclient.ActionPlan], error) {
+	return List(c, func(client watcherclient.API) ([]watcherclient.ActionPlan, error) {
+		return client.ListActionPlans(opts)
+	}, newestFirst(func(p watcherclient.ActionPlan) string { return p.CreatedAt }))
+}
+
+// ListActions lists the actions of every region, most recent first
+func (c *Clients) ListActions(opts *watcherclient.ListOptions) ([]Tagged[watcherclient.Action], error) {
+	return List(c, func(client watcherclient.API) ([]watcherclient.Action, error) {
+		return client.ListActions(opts)
+	}, newestFirst(func(a watcherclient.Action) string { return a.CreatedAt }))
+}
+
+// ListGoals lists the goals of every region, by name
+func (c *Clients) ListGoals(opts *watcherclient.ListOptions) ([]Tagged[watcherclient.Goal], error) {
+	return List(c, func(client watcherclient.API) ([]watcherclient.Goal, error) {
+		return client.ListGoals(opts)
+	}, byName(func(g watcherclient.Goal) string { return g.Name }))
+}
+
+// ListStrategies lists the strategies of every region, by name
+func (c *Clients) ListStrategies(opts *watcherclient.ListOptions) ([]Tagged[watcherclient.Strategy], error) {
+	return List(c, func(client watcherclient.API) ([]watcherclient.Strategy, error) {
+		return client.ListStrategies(opts)
+	}, byName(func(s watcherclient.Strategy) string { return s.Name }))
+}
+
+// ListServices lists the Watcher services of every region, by name
+func (c *Clients) ListServices(opts *watcherclient.ListOptions) ([]Tagged[watcherclient.Service], error) {
+	return List(c, func(client watcherclient.API) ([]watcherclient.Service, error) {
+		return client.ListServices(opts)
+	}, byName(func(s watcherclient.Service) string { return s.Name }))
+}
+
+// GetAudit returns the audit from the regions where it exists
+func (c *Clients) GetAudit(uuid string) ([]Tagged[*watcherclient.Audit], error) {
+	return Get(c, func(client watcherclient.API) (*watcherclient.Audit, error) {
+		return client.GetAudit(uuid)
+	})
+}
+
+// GetActionPlan returns the action plan from the regions where it exists
+func (c *Clients) GetActionPlan(uuid string) ([]Tagged[*watcherclient.ActionPlan], error) {
+	return Get(c, func(client watcherclient.API) (*watcherclient.ActionPlan, error) {
+		return client.GetActionPlan(uuid)
+	})
+}
+
+// GetGoal returns the goal from the regions where it exists
+func (c *Clients) GetGoal(identifier string) ([]Tagged[*watcherclient.Goal], error) {
+	return Get(c, func(client watcherclient.API) (*watcherclient.Goal, error) {
+		return client.GetGoal(identifier)
+	})
+}
+
+// GetStrategy returns the strategy from the regions where it exists
+func (c *Clients) GetStrategy(identifier string) ([]Tagged[*watcherclient.Strategy], error) {
+	return Get(c, func(client watcherclient.API) (*watcherclient.Strategy, error) {
+		return client.GetStrategy(identifier)
+	})
+}
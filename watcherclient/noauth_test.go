@@ -0,0 +1,98 @@
+package watcherclient_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+	"github.com/overwatch144/golang-watcherclient/watcherclient/watchertest"
+)
+
+// Test 1: Noauth deployments
+func TestNoAuth(t *testing.T) {
+	server := watchertest.NewServer(watchertest.Options{NoAuth: true})
+	defer server.Close()
+
+	client := watcherclient.NewClientNoAuth(server.Endpoint())
+	if _, err := client.ListGoals(nil); err != nil {
+		t.Errorf("ListGoals without token failed: %v", err)
+	}
+	if _, err := watcherclient.NewClientWithToken(server.Endpoint(), "").ListGoals(nil); err == nil {
+		t.Error("Expected the token authenticator to reject an empty token")
+	}
+
+	project := watcherclient.NewClientWithAuthenticator(watcherclient.NewNoAuthAuthenticator(server.Endpoint(), "p1"))
+	if _, err := project.ListAudits(nil); err != nil {
+		t.Errorf("ListAudits with project header failed: %v", err)
+	}
+	requests := server.Requests()
+	if last := requests[len(requests)-1]; last.ProjectID != "p1" {
+		t.Errorf("Expected X-Project-Id p1, got %q", last.ProjectID)
+	}
+
+	secured := watchertest.NewServer(watchertest.Options{})
+	defer secured.Close()
+	if _, err := watcherclient.NewClientNoAuth(secured.Endpoint()).ListGoals(nil); err == nil {
+		t.Error("Expected an authenticated server to reject noauth requests")
+	}
+}
+
+// Test 2: Noauth clients from the environment verify TLS with OS_CACERT
+func TestNoAuthFromEnv(t *testing.T) {
+	server := watchertest.NewServer(watchertest.Options{NoAuth: true, TLS: true})
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, server.CACertPEM(), 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	t.Setenv("OS_AUTH_TYPE", "none")
+	t.Setenv("OS_INFRA_OPTIM_ENDPOINT_OVERRIDE", server.Endpoint())
+
+	client, err := watcherclient.NewClientFromEnv()
+	if err != nil {
+		t.Fatalf("NewClientFromEnv failed: %v", err)
+	}
+	if _, err := client.ListGoals(nil); err == nil {
+		t.Error("Expected the self-signed certificate to be rejected without OS_CACERT")
+	}
+
+	t.Setenv("OS_CACERT", caFile)
+	if client, err = watcherclient.NewClientFromEnv(); err != nil {
+		t.Fatalf("NewClientFromEnv failed: %v", err)
+	}
+	if _, err := client.ListGoals(nil); err != nil {
+		t.Errorf("ListGoals with OS_CACERT failed: %v", err)
+	}
+}
+
+// Test 3: Noauth clients from options use the transport and project options
+func TestNoAuthWithOptions(t *testing.T) {
+	server := watchertest.NewServer(watchertest.Options{NoAuth: true, TLS: true})
+	defer server.Close()
+
+	if _, err := watcherclient.NewClientNoAuthWithOptions(watcherclient.ClientOptions{}); err == nil {
+		t.Error("Expected error without endpoint override")
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, server.CACertPEM(), 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	client, err := watcherclient.NewClientNoAuthWithOptions(watcherclient.ClientOptions{
+		EndpointOverride: server.Endpoint() + "/v1/",
+		ProjectID:        "p1",
+		CACert:           caFile,
+	})
+	if err != nil {
+		t.Fatalf("NewClientNoAuthWithOptions failed: %v", err)
+	}
+	if _, err := client.ListAudits(nil); err != nil {
+		t.Errorf("ListAudits with CA certificate failed: %v", err)
+	}
+	requests := server.Requests()
+	if last := requests[len(requests)-1]; last.ProjectID != "p1" || last.Path != "/v1/audits" {
+		t.Errorf("Expected /v1/audits for project p1, got %+v", last)
+	}
+}
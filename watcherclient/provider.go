@@ -0,0 +1,66 @@
+package watcherclient
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/gophercloud/gophercloud/v2"
+)
+
+// CommandTokenProvider returns a token provider running a command, e.g. a
+// federated login helper, and using its trimmed standard output as token
+func CommandTokenProvider(name string, args ...string) func() (string, error) {
+	return func() (string, error) {
+		var stdout, stderr bytes.Buffer
+		cmd := exec.Command(name, args...)
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+
+		if err := cmd.Run(); err != nil {
+			return "", fmt.Errorf("token command %s failed: %w: %s", name, err, strings.TrimSpace(stderr.String()))
+		}
+
+		token := strings.TrimSpace(stdout.String())
+		if token == "" {
+			return "", fmt.Errorf("token command %s returned no token", name)
+		}
+		return token, nil
+	}
+}
+
+// applyProviders sets the token and passcode obtained from the configured
+// providers
+func (a *Authenticator) applyProviders(authOpts *gophercloud.AuthOptions) error {
+	if provider := a.authOptions.TokenProvider; provider != nil {
+		token, err := provider()
+		if err != nil {
+			return fmt.Errorf("failed to obtain token: %w", err)
+		}
+		authOpts.TokenID = token
+
+		// Keystone rejects user fields in token requests
+		if authOpts.Password == "" {
+			authOpts.Username, authOpts.UserID = "", ""
+			authOpts.DomainID, authOpts.DomainName = "", ""
+		}
+	}
+
+	authOpts.Passcode = a.authOptions.Passcode
+	if provider := a.authOptions.PasscodeFunc; provider != nil {
+		passcode, err := provider()
+		if err != nil {
+			return fmt.Errorf("failed to obtain passcode: %w", err)
+		}
+		authOpts.Passcode = passcode
+	}
+
+	return nil
+}
+
+// canReauth reports whether an expired or rejected token can be replaced.
+// Tokens from a provider are renewed even without AllowReauth.
+func (a *Authenticator) canReauth() bool {
+	return a.autoReauth || a.authOptions.TokenProvider != nil || a.authOptions.PasscodeFunc != nil
+}
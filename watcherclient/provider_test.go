@@ -0,0 +1,89 @@
+package watcherclient_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+	"github.com/overwatch144/golang-watcherclient/watcherclient/watchertest"
+)
+
+// Test 1: TOTP passcodes and external token providers
+func TestPasscodeAndTokenProvider(t *testing.T) {
+	server := watchertest.NewServer(watchertest.Options{Username: "admin", Password: "secret", Passcode: "123456"})
+	defer server.Close()
+
+	opts := server.ClientOptions()
+	if _, err := watcherclient.NewClient(opts); err == nil {
+		t.Error("Expected error without passcode")
+	}
+	opts.Passcode = "123456"
+	if _, err := watcherclient.NewClient(opts); err != nil {
+		t.Errorf("Expected password and passcode to authenticate, got %v", err)
+	}
+
+	calls := 0
+	client, err := watcherclient.NewClient(watcherclient.ClientOptions{
+		AuthURL:         server.AuthURL(),
+		ProjectName:     "admin",
+		ProjectDomainID: "default",
+		TokenProvider: func() (string, error) {
+			calls++
+			return server.Token(), nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewClient with token provider failed: %v", err)
+	}
+
+	// Rejected tokens are replaced from the provider even without AllowReauth
+	server.RevokeTokens()
+	if _, err := client.ListGoals(nil); err != nil {
+		t.Errorf("Expected a new token from the provider, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected the provider to be called twice, got %d", calls)
+	}
+}
+
+// Test 2: Unscoped tokens from a provider are renewed ahead of expiry
+func TestTokenProviderUnscoped(t *testing.T) {
+	// Tokens issued with the clock behind expire within the refresh lead time
+	clock := watchertest.NewFakeClock(time.Now().Add(-watchertest.DefaultTokenTTL + 2*time.Minute))
+	server := watchertest.NewServer(watchertest.Options{Clock: clock})
+	defer server.Close()
+
+	calls := 0
+	client, err := watcherclient.NewClient(watcherclient.ClientOptions{
+		AuthURL:     server.AuthURL(),
+		AllowReauth: true,
+		TokenProvider: func() (string, error) {
+			calls++
+			return server.Token(), nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewClient with unscoped token provider failed: %v", err)
+	}
+	if info, _ := client.GetAuthInfo(); info.TokenExpiry.IsZero() || info.Warning != "" {
+		t.Fatalf("Expected a known token expiry, got %+v", info)
+	}
+
+	clock.Set(time.Now())
+	if _, err := client.ListGoals(nil); err != nil {
+		t.Fatalf("ListGoals failed: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected the provider to be called again near expiry, got %d calls", calls)
+	}
+	if requests := server.Requests(); len(requests) != 1 {
+		t.Errorf("Expected a single Watcher request without 401 retry, got %+v", requests)
+	}
+
+	if _, err := client.ListGoals(nil); err != nil {
+		t.Fatalf("ListGoals failed: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected a fresh token to be reused, got %d calls", calls)
+	}
+}
@@ -0,0 +1,153 @@
+package watcherclient
+
+import (
+	"log/slog"
+	"time"
+)
+
+// Delays of the background refresher after a failed refresh, or a refresh
+// that did not extend the token lifetime
+const (
+	minRefreshRetry = time.Second
+	maxRefreshRetry = time.Minute
+)
+
+// RefreshEvent describes a token refresh, reported to AuthOptions.OnRefresh
+type RefreshEvent struct {
+	Background bool          // Refreshed by the background refresher
+	Duration   time.Duration // Time spent authenticating
+	Expiry     time.Time     // Expiry of the new token, zero on failure
+	Err        error
+}
+
+// refreshCall is a refresh in progress, shared by concurrent callers
+type refreshCall struct {
+	done chan struct{}
+	err  error
+}
+
+// refresh re-authenticates, or waits for the refresh already in progress
+func (a *Authenticator) refresh(background bool) error {
+	a.flightMutex.Lock()
+	if call := a.inflight; call != nil {
+		a.flightMutex.Unlock()
+		<-call.done
+		return call.err
+	}
+	call := &refreshCall{done: make(chan struct{})}
+	a.inflight = call
+	a.flightMutex.Unlock()
+
+	start := time.Now()
+	call.err = a.Authenticate()
+
+	event := RefreshEvent{Background: background, Duration: time.Since(start), Err: call.err}
+	if call.err == nil {
+		event.Expiry = a.GetTokenExpiry()
+		a.log(slog.LevelInfo, "token refreshed", slog.Bool("background", background),
+			slog.Duration("duration", event.Duration), slog.Time("expires_at", event.Expiry))
+	} else {
+		a.log(slog.LevelWarn, "token refresh failed", slog.Bool("background", background),
+			slog.Duration("duration", event.Duration), slog.String("error", call.err.Error()))
+	}
+	if hook := a.authOptions.OnRefresh; hook != nil {
+		hook(event)
+	}
+
+	a.flightMutex.Lock()
+	a.inflight = nil
+	a.flightMutex.Unlock()
+	close(call.done)
+
+	return call.err
+}
+
+// refreshLead returns the remaining token lifetime at which it is renewed
+func (a *Authenticator) refreshLead() time.Duration {
+	if a.authOptions.RefreshLeadTime > 0 {
+		return a.authOptions.RefreshLeadTime
+	}
+	return DefaultTokenRefreshMargin
+}
+
+// refreshDelay returns the time until a token expiring at expiry is
+// renewed. Tokens living shorter than the lead time are renewed halfway
+// through their remaining lifetime.
+func (a *Authenticator) refreshDelay(expiry time.Time) time.Duration {
+	remaining := time.Until(expiry)
+	wait := remaining - a.refreshLead()
+	if wait <= 0 {
+		wait = remaining / 2
+	}
+	return max(wait, minRefreshRetry)
+}
+
+// startRefresher starts the background refresher
+func (a *Authenticator) startRefresher() {
+	a.stop = make(chan struct{})
+	a.stopped = make(chan struct{})
+	a.refreshing.Store(true)
+	go a.runRefresher()
+}
+
+// runRefresher renews the token ahead of its expiry until stopped
+func (a *Authenticator) runRefresher() {
+	defer close(a.stopped)
+	defer a.refreshing.Store(false)
+
+	retry := minRefreshRetry
+	for {
+		expiry := a.GetTokenExpiry()
+		if expiry.IsZero() {
+			// Nothing to renew ahead of, GetToken renews the token after a
+			// 401. Check again later, a new token may have a known expiry.
+			if !a.sleep(a.refreshLead()) {
+				return
+			}
+			continue
+		}
+
+		if !a.sleep(a.refreshDelay(expiry)) {
+			return
+		}
+		if err := a.refresh(true); err == nil && a.GetTokenExpiry().After(expiry) {
+			retry = minRefreshRetry
+			continue
+		}
+
+		// Retry with backoff, GetToken still refreshes an expired token
+		if !a.sleep(retry) {
+			return
+		}
+		retry = min(2*retry, maxRefreshRetry)
+	}
+}
+
+// sleep waits for d and reports false if the refresher is stopped first
+func (a *Authenticator) sleep(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-a.stop:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// Stop stops the background refresher, if any, and waits for it to exit
+func (a *Authenticator) Stop() {
+	if a.stop == nil {
+		return
+	}
+	a.refreshing.Store(false)
+	a.stopOnce.Do(func() { close(a.stop) })
+	<-a.stopped
+}
+
+// Close stops the background refresher
+func (a *Authenticator) Close() error {
+	a.Stop()
+	return nil
+}
@@ -0,0 +1,200 @@
+package watcherclient_test
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+	"github.com/overwatch144/golang-watcherclient/watcherclient/watchertest"
+)
+
+// Test 1: Background refresh ahead of token expiry
+func TestBackgroundRefresh(t *testing.T) {
+	server := watchertest.NewServer(watchertest.Options{TokenTTL: time.Hour + 500*time.Millisecond})
+	defer server.Close()
+
+	events := make(chan watcherclient.RefreshEvent, 10)
+	opts := server.ClientOptions()
+	opts.BackgroundRefresh = true
+	opts.RefreshLeadTime = time.Hour
+	opts.OnRefresh = func(e watcherclient.RefreshEvent) { events <- e }
+
+	client, err := watcherclient.NewClient(opts)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	info, _ := client.GetAuthInfo()
+	select {
+	case e := <-events:
+		if !e.Background || e.Err != nil {
+			t.Errorf("Expected successful background refresh, got %+v", e)
+		}
+		if !e.Expiry.After(info.TokenExpiry) {
+			t.Errorf("Expected expiry after %v, got %v", info.TokenExpiry, e.Expiry)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected a background refresh")
+	}
+
+	if err := client.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+	if _, err := client.ListGoals(nil); err != nil {
+		t.Errorf("ListGoals after refresh failed: %v", err)
+	}
+}
+
+// blockingTransport counts Keystone token requests and holds them until
+// released
+type blockingTransport struct {
+	mutex   sync.Mutex
+	block   bool
+	posts   int
+	gets    int
+	release chan struct{}
+}
+
+func (b *blockingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet {
+		b.mutex.Lock()
+		b.gets++
+		b.mutex.Unlock()
+	}
+	if req.Method == http.MethodPost {
+		b.mutex.Lock()
+		b.posts++
+		block := b.block
+		b.mutex.Unlock()
+		if block {
+			<-b.release
+		}
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// Test 2: Concurrent re-authentications share one Keystone request
+func TestReauthSingleFlight(t *testing.T) {
+	server := watchertest.NewServer(watchertest.Options{})
+	defer server.Close()
+
+	transport := &blockingTransport{release: make(chan struct{})}
+	authOpts := watcherclient.BuildAuthOptions(server.ClientOptions())
+	authOpts.Transport = transport
+	auth, err := watcherclient.NewAuthenticator(authOpts)
+	if err != nil {
+		t.Fatalf("NewAuthenticator failed: %v", err)
+	}
+
+	transport.mutex.Lock()
+	transport.block = true
+	transport.posts = 0
+	transport.mutex.Unlock()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- auth.Reauth()
+		}()
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	close(transport.release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Reauth failed: %v", err)
+		}
+	}
+	if transport.posts != 1 {
+		t.Errorf("Expected 1 Keystone request, got %d", transport.posts)
+	}
+}
+
+// Test 3: Background refresh of tokens living shorter than the lead time
+func TestBackgroundRefreshShortTokens(t *testing.T) {
+	server := watchertest.NewServer(watchertest.Options{TokenTTL: 2 * time.Second})
+	defer server.Close()
+
+	var mutex sync.Mutex
+	var events []watcherclient.RefreshEvent
+	opts := server.ClientOptions()
+	opts.BackgroundRefresh = true
+	opts.RefreshLeadTime = time.Hour
+	opts.OnRefresh = func(e watcherclient.RefreshEvent) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		events = append(events, e)
+	}
+
+	client, err := watcherclient.NewClient(opts)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	time.Sleep(2500 * time.Millisecond)
+	client.Close()
+
+	mutex.Lock()
+	background := len(events)
+	mutex.Unlock()
+	if background < 1 || background > 3 {
+		t.Fatalf("Expected 1 to 3 background refreshes, got %d", background)
+	}
+
+	// Once stopped, tokens are renewed ahead of expiry by the caller
+	if _, err := client.ListGoals(nil); err != nil {
+		t.Fatalf("ListGoals failed: %v", err)
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(events) != background+1 || events[len(events)-1].Background {
+		t.Errorf("Expected a foreground refresh after Close, got %+v", events[background:])
+	}
+}
+
+// Test 4: The current token stays available while a slow refresh runs
+func TestTokenDuringSlowRefresh(t *testing.T) {
+	server := watchertest.NewServer(watchertest.Options{})
+	defer server.Close()
+
+	auth, err := watcherclient.NewAuthenticator(watcherclient.BuildAuthOptions(server.ClientOptions()))
+	if err != nil {
+		t.Fatalf("NewAuthenticator failed: %v", err)
+	}
+	before, err := auth.GetToken()
+	if err != nil {
+		t.Fatalf("GetToken failed: %v", err)
+	}
+
+	server.SetKeystoneLatency(time.Second)
+	done := make(chan error, 1)
+	go func() { done <- auth.Reauth() }()
+	time.Sleep(100 * time.Millisecond)
+
+	start := time.Now()
+	token, err := auth.GetToken()
+	if err != nil || token != before {
+		t.Errorf("Expected the current token during the refresh, got %q: %v", token, err)
+	}
+	if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+		t.Errorf("Expected GetToken not to wait for the refresh, took %v", elapsed)
+	}
+	if auth.GetEndpoint() == "" {
+		t.Error("Expected the endpoint during the refresh")
+	}
+
+	if err := <-done; err != nil {
+		t.Fatalf("Reauth failed: %v", err)
+	}
+	if token, _ := auth.GetToken(); token == before {
+		t.Error("Expected a new token after the refresh")
+	}
+}
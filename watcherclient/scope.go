@@ -0,0 +1,69 @@
+package watcherclient
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gophercloud/gophercloud/v2"
+)
+
+// SystemScope is the scope of a system-scoped token
+var SystemScope = gophercloud.AuthScope{System: true}
+
+// WithScope returns a client using a token rescoped from the current one,
+// e.g. SystemScope to manage audit templates or a project scope to work on
+// its audits. The password is not sent again; the new client shares the
+// HTTP transport and renews its token from the token of c.
+func (c *Client) WithScope(scope gophercloud.AuthScope) (*Client, error) {
+	parent, ok := c.authenticator.(*Authenticator)
+	if !ok {
+		return nil, fmt.Errorf("rescoping requires a client authenticated with Keystone")
+	}
+
+	token, err := parent.GetToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token to rescope: %w", err)
+	}
+
+	opts := parent.authOptions
+	authOpts := &AuthOptions{
+		IdentityEndpoint: opts.IdentityEndpoint,
+		TokenID:          token,
+		Scope:            &scope,
+		AllowReauth:      opts.AllowReauth,
+		Region:           opts.Region,
+		Interface:        opts.Interface,
+		ServiceName:      opts.ServiceName,
+		EndpointOverride: opts.EndpointOverride,
+		Transport:        opts.Transport,
+		Timeout:          opts.Timeout,
+		RefreshLeadTime:  opts.RefreshLeadTime,
+		OnRefresh:        opts.OnRefresh,
+		OnWarning:        opts.OnWarning,
+		Logger:           opts.Logger,
+	}
+	if err := ValidateAuthOptions(authOpts); err != nil {
+		return nil, fmt.Errorf("invalid scope: %w", err)
+	}
+
+	auth := &Authenticator{
+		authOptions: authOpts,
+		autoReauth:  authOpts.AllowReauth,
+		parent:      parent,
+	}
+	if err := auth.Authenticate(); err != nil {
+		return nil, fmt.Errorf("failed to rescope token: %w", err)
+	}
+
+	return &Client{
+		endpoint:      auth.GetEndpoint() + "/" + c.apiVersion,
+		authenticator: auth,
+		httpClient: &http.Client{
+			Timeout:   c.httpClient.Timeout,
+			Transport: c.httpClient.Transport,
+		},
+		apiVersion: c.apiVersion,
+		logger:     c.logger,
+		logBodies:  c.logBodies,
+	}, nil
+}
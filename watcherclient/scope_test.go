@@ -0,0 +1,84 @@
+package watcherclient_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+	"github.com/overwatch144/golang-watcherclient/watcherclient/watchertest"
+)
+
+// recordingTransport keeps the bodies of Keystone token requests
+type recordingTransport struct {
+	mutex  sync.Mutex
+	bodies []string
+}
+
+func (r *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodPost && req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		r.mutex.Lock()
+		r.bodies = append(r.bodies, string(body))
+		r.mutex.Unlock()
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// Test 1: Rescoping a client without sending the password again
+func TestWithScope(t *testing.T) {
+	server := watchertest.NewServer(watchertest.Options{Username: "admin", Password: "secret"})
+	defer server.Close()
+
+	transport := &recordingTransport{}
+	authOpts := watcherclient.BuildAuthOptions(server.ClientOptions())
+	authOpts.Transport = transport
+	auth, err := watcherclient.NewAuthenticator(authOpts)
+	if err != nil {
+		t.Fatalf("NewAuthenticator failed: %v", err)
+	}
+	client := watcherclient.NewClientWithAuthenticator(auth)
+
+	system, err := client.WithScope(watcherclient.SystemScope)
+	if err != nil {
+		t.Fatalf("WithScope(system) failed: %v", err)
+	}
+	if info, _ := system.GetAuthInfo(); info.ProjectID != "" {
+		t.Errorf("Expected a system-scoped token, got project %s", info.ProjectID)
+	}
+	if _, err := system.ListAuditTemplates(nil); err != nil {
+		t.Errorf("ListAuditTemplates with system scope failed: %v", err)
+	}
+
+	demo, err := client.WithScope(gophercloud.AuthScope{ProjectName: "demo", DomainID: "default"})
+	if err != nil {
+		t.Fatalf("WithScope(project) failed: %v", err)
+	}
+	if info, _ := demo.GetAuthInfo(); info.ProjectName != "demo" {
+		t.Errorf("Expected project demo, got %s", info.ProjectName)
+	}
+	if err := demo.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+
+	if len(transport.bodies) != 3 {
+		t.Fatalf("Expected 3 token requests, got %d", len(transport.bodies))
+	}
+	for _, body := range transport.bodies[1:] {
+		if strings.Contains(body, "secret") || !strings.Contains(body, `"token"`) {
+			t.Errorf("Expected token-to-token authentication, got %s", body)
+		}
+	}
+
+	if _, err := server.Client().WithScope(watcherclient.SystemScope); err == nil {
+		t.Error("Expected error rescoping a bare token client")
+	}
+}
@@ -0,0 +1,42 @@
+package watcherclient
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// GetService retrieves a Watcher service by ID or name
+func (c *Client) GetService(identifier string) (*Service, error) {
+	path := fmt.Sprintf("/services/%s", identifier)
+	resp, err := c.doRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result Service
+	if err := parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// ListServices lists the Watcher services
+func (c *Client) ListServices(opts *ListOptions) ([]Service, error) {
+	path := "/services"
+	if opts != nil {
+		path += buildQueryString(opts)
+	}
+
+	resp, err := c.doRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result ServicesResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Services, nil
+}
@@ -0,0 +1,402 @@
+package watcherclient
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SessionManager manages named clients, e.g. one per cloud or project.
+// Clients registered with options are created on first use, and their
+// tokens are renewed ahead of expiry by RefreshSessions or StartRefresh.
+type SessionManager struct {
+	// Concurrency limits the sessions ForEach runs on at once, 0 runs on all
+	Concurrency int
+
+	sessions map[string]*session
+	mutex    sync.RWMutex
+
+	stop     chan struct{}
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+// session is a named client, created lazily when registered with options
+type session struct {
+	opts   *ClientOptions // Nil for sessions added with an authenticator
+	client *Client
+	auth   *Authenticator
+
+	mutex         sync.Mutex
+	lastRefresh   time.Time
+	lastError     error
+	lastOperation error
+}
+
+// SessionHealth reports the state of a session
+type SessionHealth struct {
+	Name        string
+	Created     bool // The client was created
+	TokenExpiry time.Time
+	LastRefresh time.Time
+	LastError   error // Last creation or refresh error
+	Healthy     bool  // Created with a valid token and no LastError since
+
+	// LastOperationError is the last error of an operation run by ForEach.
+	// It does not affect Healthy, as a 404 or 409 says nothing about the
+	// session.
+	LastOperationError error
+}
+
+// SessionResult is the outcome of an operation run on a session by ForEach
+type SessionResult struct {
+	Name  string
+	Value interface{}
+	Err   error
+}
+
+// NewSessionManager creates a new session manager
+func NewSessionManager() *SessionManager {
+	return &SessionManager{
+		sessions: make(map[string]*session),
+	}
+}
+
+// Register adds a session whose client is created from opts on first use
+func (sm *SessionManager) Register(name string, opts ClientOptions) {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+	sm.sessions[name] = &session{opts: &opts}
+}
+
+// AddSession adds a new authentication session
+func (sm *SessionManager) AddSession(name string, auth *Authenticator) {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+	sm.sessions[name] = &session{
+		client: NewClientWithAuthenticator(auth),
+		auth:   auth,
+	}
+}
+
+// GetSession retrieves an authentication session, creating its client if
+// needed
+func (sm *SessionManager) GetSession(name string) (*Authenticator, error) {
+	s, err := sm.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.get(); err != nil {
+		return nil, err
+	}
+	return s.auth, nil
+}
+
+// Client returns the client of a session, creating it if needed
+func (sm *SessionManager) Client(name string) (*Client, error) {
+	s, err := sm.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	return s.get()
+}
+
+// RemoveSession removes an authentication session
+func (sm *SessionManager) RemoveSession(name string) {
+	sm.mutex.Lock()
+	s := sm.sessions[name]
+	delete(sm.sessions, name)
+	sm.mutex.Unlock()
+
+	if s != nil {
+		s.close()
+	}
+}
+
+// ListSessions returns all session names, sorted
+func (sm *SessionManager) ListSessions() []string {
+	sm.mutex.RLock()
+	defer sm.mutex.RUnlock()
+
+	names := make([]string, 0, len(sm.sessions))
+	for name := range sm.sessions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CleanupExpiredSessions re-authenticates sessions with an expired token.
+// Sessions failing to do so are removed if they were added with an
+// authenticator, or recreated on next use if they were registered with
+// options. It returns the number of sessions removed.
+func (sm *SessionManager) CleanupExpiredSessions() int {
+	removed := 0
+	for _, name := range sm.ListSessions() {
+		s, err := sm.lookup(name)
+		if err != nil {
+			continue
+		}
+		auth := s.authenticator()
+		if auth == nil || !auth.IsTokenExpired() {
+			continue
+		}
+		if err := s.refresh(); err == nil {
+			continue
+		}
+
+		if s.opts == nil {
+			sm.RemoveSession(name)
+			removed++
+		} else {
+			s.reset()
+		}
+	}
+	return removed
+}
+
+// RefreshSessions renews the tokens of created sessions expiring within
+// lead, and returns the number of failures
+func (sm *SessionManager) RefreshSessions(lead time.Duration) int {
+	failed := 0
+	for _, name := range sm.ListSessions() {
+		s, err := sm.lookup(name)
+		if err != nil {
+			continue
+		}
+		auth := s.authenticator()
+		if auth == nil {
+			continue
+		}
+		if expiry := auth.GetTokenExpiry(); expiry.IsZero() || time.Until(expiry) >= lead {
+			continue
+		}
+		if err := s.refresh(); err != nil {
+			failed++
+		}
+	}
+	return failed
+}
+
+// StartRefresh calls RefreshSessions every interval, with the interval
+// added to DefaultTokenRefreshMargin as lead time, until Close
+func (sm *SessionManager) StartRefresh(interval time.Duration) {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+	if sm.stop != nil {
+		return
+	}
+	sm.stop = make(chan struct{})
+	sm.stopped = make(chan struct{})
+
+	go func() {
+		defer close(sm.stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-sm.stop:
+				return
+			case <-ticker.C:
+				sm.RefreshSessions(interval + DefaultTokenRefreshMargin)
+			}
+		}
+	}()
+}
+
+// Close stops the refresh started by StartRefresh and closes the clients
+func (sm *SessionManager) Close() error {
+	sm.mutex.RLock()
+	stop, stopped := sm.stop, sm.stopped
+	sm.mutex.RUnlock()
+	if stop != nil {
+		sm.stopOnce.Do(func() { close(stop) })
+		<-stopped
+	}
+
+	sm.mutex.RLock()
+	defer sm.mutex.RUnlock()
+	for _, s := range sm.sessions {
+		s.close()
+	}
+	return nil
+}
+
+// Health returns the state of every session, sorted by name
+func (sm *SessionManager) Health() []SessionHealth {
+	var health []SessionHealth
+	for _, name := range sm.ListSessions() {
+		s, err := sm.lookup(name)
+		if err != nil {
+			continue
+		}
+		health = append(health, s.health(name))
+	}
+	return health
+}
+
+// ForEach runs fn concurrently on the client of every session, at most
+// Concurrency at once, creating clients as needed, and returns the results
+// sorted by session name
+func (sm *SessionManager) ForEach(fn func(name string, client *Client) (interface{}, error)) []SessionResult {
+	names := sm.ListSessions()
+	results := make([]SessionResult, len(names))
+
+	limit := sm.Concurrency
+	if limit <= 0 || limit > len(names) {
+		limit = len(names)
+	}
+	slots := make(chan struct{}, limit)
+
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			results[i] = SessionResult{Name: name}
+
+			client, err := sm.Client(name)
+			if err != nil {
+				results[i].Err = err
+				return
+			}
+			results[i].Value, results[i].Err = fn(name, client)
+
+			if s, err := sm.lookup(name); err == nil {
+				s.record(results[i].Err)
+			}
+		}(i, name)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// lookup returns a session by name
+func (sm *SessionManager) lookup(name string) (*session, error) {
+	sm.mutex.RLock()
+	defer sm.mutex.RUnlock()
+
+	s, exists := sm.sessions[name]
+	if !exists {
+		return nil, fmt.Errorf("session '%s' not found", name)
+	}
+	return s, nil
+}
+
+// get returns the client of the session, creating it if needed. The client
+// is created without holding the mutex, so that a slow Keystone does not
+// block health checks; when concurrent calls race, the first client wins.
+func (s *session) get() (*Client, error) {
+	s.mutex.Lock()
+	client, opts := s.client, s.opts
+	s.mutex.Unlock()
+
+	if client != nil {
+		return client, nil
+	}
+	if opts == nil {
+		return nil, fmt.Errorf("session has no client options")
+	}
+
+	client, err := NewClient(*opts)
+
+	s.mutex.Lock()
+	if winner := s.client; winner != nil {
+		s.mutex.Unlock()
+		if client != nil {
+			client.Close()
+		}
+		return winner, nil
+	}
+	if err != nil {
+		s.lastError = err
+		s.mutex.Unlock()
+		return nil, err
+	}
+	s.client = client
+	s.auth, _ = client.authenticator.(*Authenticator)
+	s.lastRefresh = time.Now()
+	s.lastError = nil
+	s.mutex.Unlock()
+	return client, nil
+}
+
+// authenticator returns the authenticator of a created session
+func (s *session) authenticator() *Authenticator {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.auth
+}
+
+// refresh re-authenticates the session
+func (s *session) refresh() error {
+	auth := s.authenticator()
+	if auth == nil {
+		return nil
+	}
+
+	err := auth.Reauth()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.lastError = err
+	if err == nil {
+		s.lastRefresh = time.Now()
+	}
+	return err
+}
+
+// reset drops the client so that it is recreated on next use
+func (s *session) reset() {
+	s.mutex.Lock()
+	client := s.client
+	s.client, s.auth = nil, nil
+	s.mutex.Unlock()
+
+	if client != nil {
+		client.Close()
+	}
+}
+
+// close stops the background refresh of the client, if any
+func (s *session) close() {
+	s.mutex.Lock()
+	client := s.client
+	s.mutex.Unlock()
+
+	if client != nil {
+		client.Close()
+	}
+}
+
+// record stores the outcome of an operation
+func (s *session) record(err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.lastOperation = err
+}
+
+// health returns the state of the session
+func (s *session) health(name string) SessionHealth {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	health := SessionHealth{
+		Name:        name,
+		Created:     s.client != nil,
+		LastRefresh: s.lastRefresh,
+		LastError:   s.lastError,
+
+		LastOperationError: s.lastOperation,
+	}
+	if s.auth != nil {
+		health.TokenExpiry = s.auth.GetTokenExpiry()
+	}
+	health.Healthy = health.Created && s.lastError == nil &&
+		(s.auth == nil || !s.auth.IsTokenExpired())
+	return health
+}
@@ -0,0 +1,153 @@
+package watcherclient_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+	"github.com/overwatch144/golang-watcherclient/watcherclient/watchertest"
+)
+
+// Test 1: Session manager with lazy clients, refresh and fan-out
+func TestSessionManager(t *testing.T) {
+	server := watchertest.NewServer(watchertest.Options{Username: "admin", Password: "secret"})
+	defer server.Close()
+
+	sm := watcherclient.NewSessionManager()
+	defer sm.Close()
+
+	sm.Register("admin", server.ClientOptions())
+	broken := server.ClientOptions()
+	broken.Password = "wrong"
+	sm.Register("broken", broken)
+
+	if health := sm.Health(); len(health) != 2 || health[0].Created {
+		t.Errorf("Expected 2 sessions not yet created, got %+v", health)
+	}
+
+	results := sm.ForEach(func(name string, client *watcherclient.Client) (interface{}, error) {
+		return client.ListGoals(nil)
+	})
+	if len(results) != 2 || results[0].Name != "admin" || results[1].Name != "broken" {
+		t.Fatalf("Expected results for admin and broken, got %+v", results)
+	}
+	if results[0].Err != nil || len(results[0].Value.([]watcherclient.Goal)) == 0 {
+		t.Errorf("Expected goals for admin, got %+v", results[0])
+	}
+	if results[1].Err == nil {
+		t.Error("Expected authentication error for broken")
+	}
+
+	health := sm.Health()
+	if !health[0].Healthy || health[0].TokenExpiry.IsZero() {
+		t.Errorf("Expected healthy admin session, got %+v", health[0])
+	}
+	if health[1].Healthy || health[1].LastError == nil {
+		t.Errorf("Expected unhealthy broken session, got %+v", health[1])
+	}
+
+	// Operation errors do not make a session unhealthy
+	sm.ForEach(func(name string, client *watcherclient.Client) (interface{}, error) {
+		return client.GetAudit("missing")
+	})
+	if admin := sm.Health()[0]; !admin.Healthy || admin.LastError != nil || !watcherclient.IsNotFound(admin.LastOperationError) {
+		t.Errorf("Expected healthy admin session with a 404 operation error, got %+v", admin)
+	}
+
+	before := health[0].LastRefresh
+	if failed := sm.RefreshSessions(2 * watchertest.DefaultTokenTTL); failed != 0 {
+		t.Errorf("Expected no refresh failure, got %d", failed)
+	}
+	if after := sm.Health()[0].LastRefresh; !after.After(before) {
+		t.Errorf("Expected refresh after %v, got %v", before, after)
+	}
+
+	if _, err := sm.GetSession("admin"); err != nil {
+		t.Errorf("Client failed: %v", err)
+	}
+	sm.RemoveSession("broken")
+	if names := sm.ListSessions(); len(names) != 1 || names[0] != "admin" {
+		t.Errorf("Expected only admin, got %v", names)
+	}
+}
+
+// Test 2: Concurrent lazy creation shares one client and does not block
+// health checks
+func TestSessionConcurrentCreate(t *testing.T) {
+	server := watchertest.NewServer(watchertest.Options{})
+	defer server.Close()
+
+	sm := watcherclient.NewSessionManager()
+	defer sm.Close()
+	sm.Register("admin", server.ClientOptions())
+
+	server.SetKeystoneLatency(500 * time.Millisecond)
+	clients := make(chan *watcherclient.Client, 5)
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client, err := sm.Client("admin")
+			if err != nil {
+				t.Errorf("Client failed: %v", err)
+			}
+			clients <- client
+		}()
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	start := time.Now()
+	if health := sm.Health(); len(health) != 1 || health[0].Created {
+		t.Errorf("Expected a session being created, got %+v", health)
+	}
+	if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+		t.Errorf("Expected Health not to wait for authentication, took %v", elapsed)
+	}
+
+	wg.Wait()
+	close(clients)
+	first := <-clients
+	for client := range clients {
+		if client != first {
+			t.Error("Expected concurrent calls to share one client")
+		}
+	}
+}
+
+// Test 3: ForEach runs on at most Concurrency sessions at once
+func TestSessionForEachConcurrency(t *testing.T) {
+	server := watchertest.NewServer(watchertest.Options{})
+	defer server.Close()
+
+	sm := watcherclient.NewSessionManager()
+	defer sm.Close()
+	sm.Concurrency = 2
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		sm.Register(name, server.ClientOptions())
+	}
+
+	var mutex sync.Mutex
+	running, peak := 0, 0
+	results := sm.ForEach(func(name string, client *watcherclient.Client) (interface{}, error) {
+		mutex.Lock()
+		running++
+		peak = max(peak, running)
+		mutex.Unlock()
+
+		time.Sleep(50 * time.Millisecond)
+
+		mutex.Lock()
+		running--
+		mutex.Unlock()
+		return name, nil
+	})
+
+	if len(results) != 5 || results[4].Value != "e" {
+		t.Errorf("Expected 5 results, got %+v", results)
+	}
+	if peak != 2 {
+		t.Errorf("Expected at most 2 concurrent operations, got %d", peak)
+	}
+}
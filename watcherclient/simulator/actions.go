@@ -0,0 +1,165 @@
+package simulator
+
+import (
+	"fmt"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+	"github.com/overwatch144/golang-watcherclient/watcherclient/analytics"
+)
+
+// state is the in-memory model the actions are applied to
+type state struct {
+	model *watcherclient.ComputeDataModel
+}
+
+// apply applies one action and returns the violations it caused, or a
+// reason when the action cannot be simulated
+func (s *state) apply(action watcherclient.Action) ([]Violation, string) {
+	switch action.ActionType {
+	case ActionMigrate:
+		return s.migrate(action)
+	case ActionChangeNovaServiceState:
+		return s.changeServiceState(action)
+	case ActionChangeNodePowerState:
+		return s.changePowerState(action)
+	case ActionNop, ActionSleep:
+		return nil, ""
+	case ActionResize:
+		return nil, "flavor sizes are not part of the data model"
+	default:
+		return nil, fmt.Sprintf("unsupported action type '%s'", action.ActionType)
+	}
+}
+
+// migrate moves an instance to its destination node
+func (s *state) migrate(action watcherclient.Action) ([]Violation, string) {
+	instanceUUID := stringParam(action, "resource_id")
+	destination := stringParam(action, "destination_node")
+	if destination == "" {
+		return nil, "destination is chosen by the scheduler"
+	}
+
+	instance := s.instance(instanceUUID)
+	if instance == nil {
+		return nil, fmt.Sprintf("instance '%s' not found in data model", instanceUUID)
+	}
+
+	dst, ok := s.model.Node(destination)
+	if !ok {
+		return nil, fmt.Sprintf("destination node '%s' not found in data model", destination)
+	}
+
+	var violations []Violation
+	if source := stringParam(action, "source_node"); source != "" && source != instance.Host && source != instance.NodeUUID {
+		violations = append(violations, Violation{
+			ActionUUID: action.UUID,
+			Host:       source,
+			Resource:   "placement",
+			Message:    fmt.Sprintf("instance %s is on %s, not on source node %s", instance.UUID, instance.Host, source),
+		})
+	}
+
+	if dst.State == "down" || dst.Status == "disabled" {
+		violations = append(violations, Violation{
+			ActionUUID: action.UUID,
+			Host:       dst.Hostname,
+			Resource:   "state",
+			Message:    fmt.Sprintf("destination is %s/%s", dst.State, dst.Status),
+		})
+	}
+
+	instance.NodeUUID = dst.UUID
+	instance.Host = dst.Hostname
+
+	return append(violations, s.capacityViolations(action.UUID, dst.UUID)...), ""
+}
+
+// changeServiceState enables or disables the nova-compute service of a node
+func (s *state) changeServiceState(action watcherclient.Action) ([]Violation, string) {
+	host := stringParam(action, "resource_id")
+	node, ok := s.model.Node(host)
+	if !ok {
+		return nil, fmt.Sprintf("node '%s' not found in data model", host)
+	}
+
+	newState := stringParam(action, "state")
+	if newState != "enabled" && newState != "disabled" {
+		return nil, fmt.Sprintf("unknown service state '%s'", newState)
+	}
+
+	node.Status = newState
+	node.DisabledReason = stringParam(action, "disabled_reason")
+	return nil, ""
+}
+
+// changePowerState powers a node on or off
+func (s *state) changePowerState(action watcherclient.Action) ([]Violation, string) {
+	identifier := stringParam(action, "resource_id")
+	if name := stringParam(action, "resource_name"); name != "" {
+		identifier = name
+	}
+
+	node, ok := s.model.Node(identifier)
+	if !ok {
+		return nil, fmt.Sprintf("node '%s' not found in data model", identifier)
+	}
+
+	switch stringParam(action, "state") {
+	case "on":
+		node.State = "up"
+		return nil, ""
+	case "off":
+		node.State = "down"
+		if n := len(s.model.InstancesOn(node.UUID)); n > 0 {
+			return []Violation{{
+				ActionUUID: action.UUID,
+				Host:       node.Hostname,
+				Resource:   "state",
+				Message:    fmt.Sprintf("node powered off with %d instances", n),
+			}}, ""
+		}
+		return nil, ""
+	default:
+		return nil, fmt.Sprintf("unknown power state '%s'", stringParam(action, "state"))
+	}
+}
+
+// capacityViolations checks the resources of a node after a placement change
+func (s *state) capacityViolations(actionUUID, nodeUUID string) []Violation {
+	var violations []Violation
+	for _, h := range analytics.Utilization(s.model) {
+		if h.NodeUUID != nodeUUID {
+			continue
+		}
+		check := func(resource string, used int, capacity float64) {
+			if float64(used) > capacity {
+				violations = append(violations, Violation{
+					ActionUUID: actionUUID,
+					Host:       h.Hostname,
+					Resource:   resource,
+					Message:    fmt.Sprintf("%s used %d exceeds capacity %.0f", resource, used, capacity),
+				})
+			}
+		}
+		check("vcpus", h.VCPUsUsed, h.VCPUsCapacity)
+		check("memory", h.MemoryUsed, h.MemoryCapacity)
+		check("disk", h.DiskUsed, h.DiskCapacity)
+	}
+	return violations
+}
+
+// instance returns a pointer to the instance with the given UUID
+func (s *state) instance(uuid string) *watcherclient.Instance {
+	for i := range s.model.Instances {
+		if s.model.Instances[i].UUID == uuid {
+			return &s.model.Instances[i]
+		}
+	}
+	return nil
+}
+
+// stringParam returns a string action parameter
+func stringParam(action watcherclient.Action, name string) string {
+	value, _ := action.Parameters[name].(string)
+	return value
+}
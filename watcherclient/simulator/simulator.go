@@ -0,0 +1,172 @@
+// Package simulator replays the actions of a Watcher action plan against an
+// in-memory copy of the compute data model, to predict its outcome before
+// anything runs on the real cloud.
+package simulator
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+	"github.com/overwatch144/golang-watcherclient/watcherclient/analytics"
+)
+
+// Action types understood by the simulator
+const (
+	ActionMigrate                = "migrate"
+	ActionResize                 = "resize"
+	ActionChangeNovaServiceState = "change_nova_service_state"
+	ActionChangeNodePowerState   = "change_node_power_state"
+	ActionNop                    = "nop"
+	ActionSleep                  = "sleep"
+)
+
+// Result is the predicted outcome of an action plan
+type Result struct {
+	Model      *watcherclient.ComputeDataModel
+	Before     []analytics.HostUtilization
+	After      []analytics.HostUtilization
+	Order      []string // Action UUIDs in execution order
+	Applied    int
+	Skipped    []SkippedAction
+	Violations []Violation
+	FreedHosts []string // Hosts that had instances before and none after
+}
+
+// SkippedAction is an action the simulator could not model
+type SkippedAction struct {
+	UUID       string
+	ActionType string
+	Reason     string
+}
+
+// Violation is a capacity or placement problem caused by an action
+type Violation struct {
+	ActionUUID string
+	Host       string
+	Resource   string // vcpus, memory, disk, state
+	Message    string
+}
+
+// Simulate applies the actions to a copy of model in dependency order
+func Simulate(model *watcherclient.ComputeDataModel, actions []watcherclient.Action) (*Result, error) {
+	if model == nil {
+		return nil, fmt.Errorf("data model cannot be nil")
+	}
+
+	ordered, err := topologicalOrder(actions)
+	if err != nil {
+		return nil, err
+	}
+
+	sim := &state{model: copyModel(model)}
+	result := &Result{
+		Model:  sim.model,
+		Before: analytics.Utilization(model),
+	}
+
+	for _, action := range ordered {
+		result.Order = append(result.Order, action.UUID)
+
+		violations, skipReason := sim.apply(action)
+		if skipReason != "" {
+			result.Skipped = append(result.Skipped, SkippedAction{
+				UUID:       action.UUID,
+				ActionType: action.ActionType,
+				Reason:     skipReason,
+			})
+			continue
+		}
+
+		result.Applied++
+		result.Violations = append(result.Violations, violations...)
+	}
+
+	result.After = analytics.Utilization(sim.model)
+
+	after := map[string]int{}
+	for _, h := range result.After {
+		after[h.Hostname] = h.Instances
+	}
+	for _, h := range result.Before {
+		if h.Instances > 0 && after[h.Hostname] == 0 {
+			result.FreedHosts = append(result.FreedHosts, h.Hostname)
+		}
+	}
+
+	return result, nil
+}
+
+// SimulateActionPlan fetches the data model and the actions of an action
+// plan and simulates them
+func SimulateActionPlan(c *watcherclient.Client, actionPlanUUID string) (*Result, error) {
+	model, err := c.GetComputeDataModel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get compute data model: %w", err)
+	}
+
+	actions, err := c.ListActionsByActionPlan(actionPlanUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list actions: %w", err)
+	}
+
+	return Simulate(model, actions)
+}
+
+// topologicalOrder sorts actions so that parents run before their children.
+// Ready actions are ordered by UUID to keep the result deterministic.
+func topologicalOrder(actions []watcherclient.Action) ([]watcherclient.Action, error) {
+	byUUID := make(map[string]watcherclient.Action, len(actions))
+	for _, action := range actions {
+		byUUID[action.UUID] = action
+	}
+
+	pending := make(map[string]int, len(actions))
+	children := map[string][]string{}
+	for _, action := range actions {
+		for _, parent := range action.ParentsUUIDs {
+			if _, ok := byUUID[parent]; !ok {
+				// Parents outside the plan are considered done
+				continue
+			}
+			pending[action.UUID]++
+			children[parent] = append(children[parent], action.UUID)
+		}
+	}
+
+	var ready []string
+	for _, action := range actions {
+		if pending[action.UUID] == 0 {
+			ready = append(ready, action.UUID)
+		}
+	}
+
+	ordered := make([]watcherclient.Action, 0, len(actions))
+	for len(ready) > 0 {
+		sort.Strings(ready)
+		next := ready[0]
+		ready = ready[1:]
+		ordered = append(ordered, byUUID[next])
+
+		for _, child := range children[next] {
+			pending[child]--
+			if pending[child] == 0 {
+				ready = append(ready, child)
+			}
+		}
+	}
+
+	if len(ordered) != len(byUUID) {
+		return nil, fmt.Errorf("action plan contains a dependency cycle")
+	}
+
+	return ordered, nil
+}
+
+// copyModel returns a deep copy of the nodes and instances
+func copyModel(m *watcherclient.ComputeDataModel) *watcherclient.ComputeDataModel {
+	return &watcherclient.ComputeDataModel{
+		Nodes:     append([]watcherclient.ComputeNode(nil), m.Nodes...),
+		Instances: append([]watcherclient.Instance(nil), m.Instances...),
+	}
+}
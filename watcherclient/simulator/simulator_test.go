@@ -0,0 +1,83 @@
+package simulator
+
+import (
+	"testing"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+)
+
+func testModel() *watcherclient.ComputeDataModel {
+	return &watcherclient.ComputeDataModel{
+		Nodes: []watcherclient.ComputeNode{
+			{UUID: "n1", Hostname: "compute-1", State: "up", Status: "enabled", VCPUs: 8, Memory: 16384, Disk: 100},
+			{UUID: "n2", Hostname: "compute-2", State: "up", Status: "enabled", VCPUs: 8, Memory: 16384, Disk: 100},
+		},
+		Instances: []watcherclient.Instance{
+			{UUID: "vm1", NodeUUID: "n1", Host: "compute-1", VCPUs: 4, Memory: 4096, Disk: 20},
+			{UUID: "vm2", NodeUUID: "n2", Host: "compute-2", VCPUs: 6, Memory: 4096, Disk: 20},
+		},
+	}
+}
+
+// Test 1: Consolidation frees a host
+func TestSimulateConsolidation(t *testing.T) {
+	model := testModel()
+	model.Instances[1].VCPUs = 2
+
+	actions := []watcherclient.Action{
+		{UUID: "b", ActionType: ActionChangeNovaServiceState, ParentsUUIDs: []string{"a"},
+			Parameters: map[string]interface{}{"resource_id": "compute-1", "state": "disabled"}},
+		{UUID: "a", ActionType: ActionMigrate,
+			Parameters: map[string]interface{}{"resource_id": "vm1", "source_node": "compute-1", "destination_node": "compute-2"}},
+	}
+
+	result, err := Simulate(model, actions)
+	if err != nil {
+		t.Fatalf("Simulate failed: %v", err)
+	}
+
+	if len(result.Order) != 2 || result.Order[0] != "a" {
+		t.Errorf("Expected migration to run first, got %v", result.Order)
+	}
+
+	if len(result.Violations) != 0 {
+		t.Errorf("Expected no violations, got %+v", result.Violations)
+	}
+
+	if len(result.FreedHosts) != 1 || result.FreedHosts[0] != "compute-1" {
+		t.Errorf("Expected compute-1 to be freed, got %v", result.FreedHosts)
+	}
+
+	if model.Instances[0].Host != "compute-1" {
+		t.Error("Input model should not be modified")
+	}
+}
+
+// Test 2: Capacity violations
+func TestSimulateCapacityViolation(t *testing.T) {
+	actions := []watcherclient.Action{
+		{UUID: "a", ActionType: ActionMigrate,
+			Parameters: map[string]interface{}{"resource_id": "vm1", "destination_node": "compute-2"}},
+	}
+
+	result, err := Simulate(testModel(), actions)
+	if err != nil {
+		t.Fatalf("Simulate failed: %v", err)
+	}
+
+	if len(result.Violations) != 1 || result.Violations[0].Resource != "vcpus" {
+		t.Errorf("Expected a vcpus violation, got %+v", result.Violations)
+	}
+}
+
+// Test 3: Dependency cycles are rejected
+func TestSimulateCycle(t *testing.T) {
+	actions := []watcherclient.Action{
+		{UUID: "a", ActionType: ActionNop, ParentsUUIDs: []string{"b"}},
+		{UUID: "b", ActionType: ActionNop, ParentsUUIDs: []string{"a"}},
+	}
+
+	if _, err := Simulate(testModel(), actions); err == nil {
+		t.Error("Expected error for dependency cycle")
+	}
+}
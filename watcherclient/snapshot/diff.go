@@ -0,0 +1,191 @@
+package snapshot
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+)
+
+// Diff describes the changes between two compute data models
+type Diff struct {
+	From Metadata
+	To   Metadata
+
+	MovedInstances   []InstanceMove
+	AddedInstances   []string // Instance UUIDs
+	RemovedInstances []string
+	ChangedInstances []Change
+
+	AddedHosts   []string // Hostnames
+	RemovedHosts []string
+	ChangedHosts []Change
+}
+
+// InstanceMove describes an instance that changed host
+type InstanceMove struct {
+	UUID     string
+	Name     string
+	FromHost string
+	ToHost   string
+}
+
+// Change lists the fields that changed on a host or instance
+type Change struct {
+	ID     string // Hostname or instance UUID
+	Fields []FieldChange
+}
+
+// FieldChange describes a single changed field
+type FieldChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// Empty reports whether the two models are identical for the compared fields
+func (d *Diff) Empty() bool {
+	return len(d.MovedInstances) == 0 && len(d.AddedInstances) == 0 &&
+		len(d.RemovedInstances) == 0 && len(d.ChangedInstances) == 0 &&
+		len(d.AddedHosts) == 0 && len(d.RemovedHosts) == 0 && len(d.ChangedHosts) == 0
+}
+
+// Compare computes the diff between two compute snapshots
+func Compare(from, to *Snapshot) (*Diff, error) {
+	a, err := from.Compute()
+	if err != nil {
+		return nil, fmt.Errorf("invalid source snapshot: %w", err)
+	}
+
+	b, err := to.Compute()
+	if err != nil {
+		return nil, fmt.Errorf("invalid target snapshot: %w", err)
+	}
+
+	d := DiffModels(a, b)
+	d.From = from.Metadata
+	d.To = to.Metadata
+	return d, nil
+}
+
+// DiffModels computes the diff between two compute data models
+func DiffModels(from, to *watcherclient.ComputeDataModel) *Diff {
+	d := &Diff{}
+
+	oldHosts := indexNodes(from.Nodes)
+	newHosts := indexNodes(to.Nodes)
+
+	for host, oldNode := range oldHosts {
+		newNode, ok := newHosts[host]
+		if !ok {
+			d.RemovedHosts = append(d.RemovedHosts, host)
+			continue
+		}
+		if fields := nodeChanges(oldNode, newNode); len(fields) > 0 {
+			d.ChangedHosts = append(d.ChangedHosts, Change{ID: host, Fields: fields})
+		}
+	}
+
+	for host := range newHosts {
+		if _, ok := oldHosts[host]; !ok {
+			d.AddedHosts = append(d.AddedHosts, host)
+		}
+	}
+
+	oldInstances := indexInstances(from.Instances)
+	newInstances := indexInstances(to.Instances)
+
+	for uuid, oldInstance := range oldInstances {
+		newInstance, ok := newInstances[uuid]
+		if !ok {
+			d.RemovedInstances = append(d.RemovedInstances, uuid)
+			continue
+		}
+		if oldInstance.Host != newInstance.Host {
+			d.MovedInstances = append(d.MovedInstances, InstanceMove{
+				UUID:     uuid,
+				Name:     newInstance.Name,
+				FromHost: oldInstance.Host,
+				ToHost:   newInstance.Host,
+			})
+		}
+		if fields := instanceChanges(oldInstance, newInstance); len(fields) > 0 {
+			d.ChangedInstances = append(d.ChangedInstances, Change{ID: uuid, Fields: fields})
+		}
+	}
+
+	for uuid := range newInstances {
+		if _, ok := oldInstances[uuid]; !ok {
+			d.AddedInstances = append(d.AddedInstances, uuid)
+		}
+	}
+
+	sort.Strings(d.AddedHosts)
+	sort.Strings(d.RemovedHosts)
+	sort.Strings(d.AddedInstances)
+	sort.Strings(d.RemovedInstances)
+	sort.Slice(d.MovedInstances, func(i, j int) bool { return d.MovedInstances[i].UUID < d.MovedInstances[j].UUID })
+	sort.Slice(d.ChangedHosts, func(i, j int) bool { return d.ChangedHosts[i].ID < d.ChangedHosts[j].ID })
+	sort.Slice(d.ChangedInstances, func(i, j int) bool { return d.ChangedInstances[i].ID < d.ChangedInstances[j].ID })
+
+	return d
+}
+
+// indexNodes maps compute nodes by hostname, falling back to UUID
+func indexNodes(nodes []watcherclient.ComputeNode) map[string]watcherclient.ComputeNode {
+	index := make(map[string]watcherclient.ComputeNode, len(nodes))
+	for _, node := range nodes {
+		key := node.Hostname
+		if key == "" {
+			key = node.UUID
+		}
+		index[key] = node
+	}
+	return index
+}
+
+// indexInstances maps instances by UUID
+func indexInstances(instances []watcherclient.Instance) map[string]watcherclient.Instance {
+	index := make(map[string]watcherclient.Instance, len(instances))
+	for _, instance := range instances {
+		index[instance.UUID] = instance
+	}
+	return index
+}
+
+// nodeChanges compares the state and capacity of two compute nodes
+func nodeChanges(a, b watcherclient.ComputeNode) []FieldChange {
+	var fields []FieldChange
+	fields = appendChange(fields, "state", a.State, b.State)
+	fields = appendChange(fields, "status", a.Status, b.Status)
+	fields = appendChange(fields, "disabled_reason", a.DisabledReason, b.DisabledReason)
+	fields = appendChange(fields, "vcpus", a.VCPUs, b.VCPUs)
+	fields = appendChange(fields, "vcpu_ratio", a.VCPURatio, b.VCPURatio)
+	fields = appendChange(fields, "memory", a.Memory, b.Memory)
+	fields = appendChange(fields, "memory_ratio", a.MemoryRatio, b.MemoryRatio)
+	fields = appendChange(fields, "disk", a.Disk, b.Disk)
+	fields = appendChange(fields, "disk_ratio", a.DiskRatio, b.DiskRatio)
+	return fields
+}
+
+// instanceChanges compares the state and flavor of two instances
+func instanceChanges(a, b watcherclient.Instance) []FieldChange {
+	var fields []FieldChange
+	fields = appendChange(fields, "state", a.State, b.State)
+	fields = appendChange(fields, "vcpus", a.VCPUs, b.VCPUs)
+	fields = appendChange(fields, "memory", a.Memory, b.Memory)
+	fields = appendChange(fields, "disk", a.Disk, b.Disk)
+	return fields
+}
+
+// appendChange records a field change when the values differ
+func appendChange[T comparable](fields []FieldChange, name string, before, after T) []FieldChange {
+	if before == after {
+		return fields
+	}
+	return append(fields, FieldChange{
+		Field: name,
+		Old:   fmt.Sprint(before),
+		New:   fmt.Sprint(after),
+	})
+}
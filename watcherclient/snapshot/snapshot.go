@@ -0,0 +1,131 @@
+// Package snapshot stores Watcher data models on disk and compares them, to
+// verify that an action plan changed the infrastructure as promised.
+package snapshot
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+)
+
+// Metadata describes where and when a snapshot was taken
+type Metadata struct {
+	Time     time.Time `json:"time"`
+	Endpoint string    `json:"endpoint"`
+	Type     string    `json:"type"`
+}
+
+// Snapshot is a data model captured at a point in time
+type Snapshot struct {
+	Metadata Metadata                 `json:"metadata"`
+	Model    *watcherclient.DataModel `json:"model"`
+}
+
+// Take captures the current data model of the given type
+func Take(c *watcherclient.Client, dataModelType string) (*Snapshot, error) {
+	if dataModelType == "" {
+		dataModelType = watcherclient.DataModelTypeCompute
+	}
+
+	model, err := c.GetDataModel(dataModelType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data model: %w", err)
+	}
+
+	return &Snapshot{
+		Metadata: Metadata{
+			Time:     time.Now().UTC(),
+			Endpoint: c.GetEndpoint(),
+			Type:     dataModelType,
+		},
+		Model: model,
+	}, nil
+}
+
+// Save writes the snapshot to path as gzip-compressed JSON. The file is
+// written to a temporary location first and renamed into place.
+func (s *Snapshot) Save(path string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := s.Write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close snapshot file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to save snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// Write encodes the snapshot to w as gzip-compressed JSON
+func (s *Snapshot) Write(w io.Writer) error {
+	gz := gzip.NewWriter(w)
+	gz.Comment = fmt.Sprintf("watcher %s data model", s.Metadata.Type)
+	gz.ModTime = s.Metadata.Time
+
+	if err := json.NewEncoder(gz).Encode(s); err != nil {
+		gz.Close()
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to compress snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// Load reads a snapshot written by Save
+func Load(path string) (*Snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	defer f.Close()
+
+	return Read(f)
+}
+
+// Read decodes a gzip-compressed JSON snapshot from r
+func Read(r io.Reader) (*Snapshot, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress snapshot: %w", err)
+	}
+	defer gz.Close()
+
+	var s Snapshot
+	if err := json.NewDecoder(gz).Decode(&s); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+
+	if s.Model == nil {
+		return nil, fmt.Errorf("snapshot does not contain a data model")
+	}
+
+	return &s, nil
+}
+
+// Compute returns the typed compute data model of the snapshot
+func (s *Snapshot) Compute() (*watcherclient.ComputeDataModel, error) {
+	if s.Metadata.Type != "" && s.Metadata.Type != watcherclient.DataModelTypeCompute {
+		return nil, fmt.Errorf("snapshot holds a %s data model, not compute", s.Metadata.Type)
+	}
+	return watcherclient.ParseComputeDataModel(s.Model)
+}
@@ -0,0 +1,90 @@
+package snapshot
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+)
+
+func testSnapshot(placement map[string]string, nodeState string) *Snapshot {
+	context := []map[string]interface{}{}
+	for uuid, host := range placement {
+		context = append(context, map[string]interface{}{
+			"node_uuid":     host,
+			"node_hostname": host,
+			"node_state":    "up",
+			"server_uuid":   uuid,
+			"server_vcpus":  float64(2),
+		})
+	}
+	context = append(context, map[string]interface{}{
+		"node_uuid":     "compute-3",
+		"node_hostname": "compute-3",
+		"node_state":    nodeState,
+	})
+
+	return &Snapshot{
+		Metadata: Metadata{Time: time.Now().UTC(), Endpoint: "http://watcher/v1", Type: "compute"},
+		Model:    &watcherclient.DataModel{Context: context},
+	}
+}
+
+// Test 1: Save and load round trip
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "model.json.gz")
+	s := testSnapshot(map[string]string{"vm-1": "compute-1"}, "up")
+
+	if err := s.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if loaded.Metadata.Endpoint != s.Metadata.Endpoint {
+		t.Errorf("Expected endpoint %s, got %s", s.Metadata.Endpoint, loaded.Metadata.Endpoint)
+	}
+
+	if len(loaded.Model.Context) != 2 {
+		t.Errorf("Expected 2 elements, got %d", len(loaded.Model.Context))
+	}
+}
+
+// Test 2: Moved instances and changed hosts
+func TestCompare(t *testing.T) {
+	before := testSnapshot(map[string]string{"vm-1": "compute-1", "vm-2": "compute-1"}, "up")
+	after := testSnapshot(map[string]string{"vm-1": "compute-2", "vm-3": "compute-1"}, "down")
+
+	d, err := Compare(before, after)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+
+	if len(d.MovedInstances) != 1 || d.MovedInstances[0].ToHost != "compute-2" {
+		t.Errorf("Unexpected moved instances: %+v", d.MovedInstances)
+	}
+
+	if len(d.AddedInstances) != 1 || d.AddedInstances[0] != "vm-3" {
+		t.Errorf("Unexpected added instances: %v", d.AddedInstances)
+	}
+
+	if len(d.RemovedInstances) != 1 || d.RemovedInstances[0] != "vm-2" {
+		t.Errorf("Unexpected removed instances: %v", d.RemovedInstances)
+	}
+
+	if len(d.AddedHosts) != 1 || d.AddedHosts[0] != "compute-2" {
+		t.Errorf("Unexpected added hosts: %v", d.AddedHosts)
+	}
+
+	if len(d.ChangedHosts) != 1 || d.ChangedHosts[0].ID != "compute-3" {
+		t.Errorf("Unexpected changed hosts: %+v", d.ChangedHosts)
+	}
+
+	if d.Empty() {
+		t.Error("Diff should not be empty")
+	}
+}
@@ -0,0 +1,153 @@
+package strategies
+
+import (
+	"fmt"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+)
+
+// Strategy names for the workload_balancing goal
+const (
+	StrategyWorkloadStabilization  = "workload_stabilization"
+	StrategyWorkloadBalance        = "workload_balance"
+	StrategyStorageCapacityBalance = "storage_capacity_balance"
+)
+
+// Metric names understood by the workload balancing strategies
+const (
+	MetricInstanceCPUUsage = "instance_cpu_usage"
+	MetricInstanceRAMUsage = "instance_ram_usage"
+	MetricHostCPUUsage     = "host_cpu_usage"
+	MetricHostRAMUsage     = "host_ram_usage"
+)
+
+// Host choices for the workload_stabilization strategy
+const (
+	HostChoiceCycle      = "cycle"
+	HostChoiceRetry      = "retry"
+	HostChoiceFullSearch = "fullsearch"
+)
+
+// Periods holds the metric collection periods per metric source in seconds
+type Periods struct {
+	Instance    int `json:"instance,omitempty"`
+	ComputeNode int `json:"compute_node,omitempty"`
+	Node        int `json:"node,omitempty"`
+}
+
+// WorkloadStabilizationParams represents the parameters of the
+// workload_stabilization strategy
+type WorkloadStabilizationParams struct {
+	Metrics           []string           `json:"metrics,omitempty"`
+	Thresholds        map[string]float64 `json:"thresholds,omitempty"`
+	Weights           map[string]float64 `json:"weights,omitempty"`
+	InstanceMetrics   map[string]string  `json:"instance_metrics,omitempty"`
+	HostChoice        string             `json:"host_choice,omitempty"` // cycle, retry, fullsearch
+	RetryCount        int                `json:"retry_count,omitempty"`
+	Periods           *Periods           `json:"periods,omitempty"`
+	Granularity       int                `json:"granularity,omitempty"` // Seconds
+	AggregationMethod *AggregationMethod `json:"aggregation_method,omitempty"`
+}
+
+// StrategyName returns the Watcher strategy name
+func (p WorkloadStabilizationParams) StrategyName() string { return StrategyWorkloadStabilization }
+
+// GoalName returns the goal the strategy belongs to
+func (p WorkloadStabilizationParams) GoalName() string { return GoalWorkloadBalancing }
+
+// Validate checks the parameters
+func (p WorkloadStabilizationParams) Validate() error {
+	for _, metric := range p.Metrics {
+		if err := oneOf("metrics", metric, MetricInstanceCPUUsage, MetricInstanceRAMUsage); err != nil {
+			return err
+		}
+	}
+
+	for metric, threshold := range p.Thresholds {
+		if threshold < 0 || threshold > 1 {
+			return fmt.Errorf("threshold for %s must be between 0 and 1", metric)
+		}
+	}
+
+	for name, weight := range p.Weights {
+		if err := nonNegative("weight "+name, weight); err != nil {
+			return err
+		}
+	}
+
+	if err := oneOf("host_choice", p.HostChoice, HostChoiceCycle, HostChoiceRetry, HostChoiceFullSearch); err != nil {
+		return err
+	}
+
+	if err := nonNegative("retry_count", float64(p.RetryCount)); err != nil {
+		return err
+	}
+
+	return nonNegative("granularity", float64(p.Granularity))
+}
+
+// NewWorkloadStabilizationAudit builds an audit for the
+// workload_stabilization strategy
+func NewWorkloadStabilizationAudit(p WorkloadStabilizationParams, opts *AuditOptions) (*watcherclient.Audit, error) {
+	return NewAudit(p, opts)
+}
+
+// WorkloadBalanceParams represents the parameters of the workload_balance
+// strategy
+type WorkloadBalanceParams struct {
+	Metrics     string  `json:"metrics,omitempty"`   // instance_cpu_usage, instance_ram_usage
+	Threshold   float64 `json:"threshold,omitempty"` // Percent
+	Period      int     `json:"period,omitempty"`    // Seconds
+	Granularity int     `json:"granularity,omitempty"`
+}
+
+// StrategyName returns the Watcher strategy name
+func (p WorkloadBalanceParams) StrategyName() string { return StrategyWorkloadBalance }
+
+// GoalName returns the goal the strategy belongs to
+func (p WorkloadBalanceParams) GoalName() string { return GoalWorkloadBalancing }
+
+// Validate checks the parameters
+func (p WorkloadBalanceParams) Validate() error {
+	if err := oneOf("metrics", p.Metrics, MetricInstanceCPUUsage, MetricInstanceRAMUsage); err != nil {
+		return err
+	}
+	if p.Threshold < 0 || p.Threshold > 100 {
+		return fmt.Errorf("threshold must be between 0 and 100")
+	}
+	if err := nonNegative("period", float64(p.Period)); err != nil {
+		return err
+	}
+	return nonNegative("granularity", float64(p.Granularity))
+}
+
+// NewWorkloadBalanceAudit builds an audit for the workload_balance strategy
+func NewWorkloadBalanceAudit(p WorkloadBalanceParams, opts *AuditOptions) (*watcherclient.Audit, error) {
+	return NewAudit(p, opts)
+}
+
+// StorageCapacityBalanceParams represents the parameters of the
+// storage_capacity_balance strategy
+type StorageCapacityBalanceParams struct {
+	VolumeThreshold float64 `json:"volume_threshold,omitempty"` // Percent
+}
+
+// StrategyName returns the Watcher strategy name
+func (p StorageCapacityBalanceParams) StrategyName() string { return StrategyStorageCapacityBalance }
+
+// GoalName returns the goal the strategy belongs to
+func (p StorageCapacityBalanceParams) GoalName() string { return GoalWorkloadBalancing }
+
+// Validate checks the parameters
+func (p StorageCapacityBalanceParams) Validate() error {
+	if p.VolumeThreshold < 0 || p.VolumeThreshold > 100 {
+		return fmt.Errorf("volume_threshold must be between 0 and 100")
+	}
+	return nil
+}
+
+// NewStorageCapacityBalanceAudit builds an audit for the
+// storage_capacity_balance strategy
+func NewStorageCapacityBalanceAudit(p StorageCapacityBalanceParams, opts *AuditOptions) (*watcherclient.Audit, error) {
+	return NewAudit(p, opts)
+}
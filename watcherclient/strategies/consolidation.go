@@ -0,0 +1,101 @@
+package strategies
+
+import (
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+)
+
+// Strategy names for the server_consolidation goal
+const (
+	StrategyBasic                     = "basic"
+	StrategyVMWorkloadConsolidation   = "vm_workload_consolidation"
+	StrategyNodeResourceConsolidation = "node_resource_consolidation"
+)
+
+// BasicParams represents the parameters of the basic consolidation strategy
+type BasicParams struct {
+	MigrationAttempts int                `json:"migration_attempts,omitempty"`
+	Period            int                `json:"period,omitempty"`      // Seconds
+	Granularity       int                `json:"granularity,omitempty"` // Seconds
+	AggregationMethod *AggregationMethod `json:"aggregation_method,omitempty"`
+}
+
+// StrategyName returns the Watcher strategy name
+func (p BasicParams) StrategyName() string { return StrategyBasic }
+
+// GoalName returns the goal the strategy belongs to
+func (p BasicParams) GoalName() string { return GoalServerConsolidation }
+
+// Validate checks the parameters
+func (p BasicParams) Validate() error {
+	if err := nonNegative("migration_attempts", float64(p.MigrationAttempts)); err != nil {
+		return err
+	}
+	if err := nonNegative("period", float64(p.Period)); err != nil {
+		return err
+	}
+	return nonNegative("granularity", float64(p.Granularity))
+}
+
+// NewBasicAudit builds an audit for the basic consolidation strategy
+func NewBasicAudit(p BasicParams, opts *AuditOptions) (*watcherclient.Audit, error) {
+	return NewAudit(p, opts)
+}
+
+// VMWorkloadConsolidationParams represents the parameters of the
+// vm_workload_consolidation strategy
+type VMWorkloadConsolidationParams struct {
+	Period            int                `json:"period,omitempty"`      // Seconds
+	Granularity       int                `json:"granularity,omitempty"` // Seconds
+	AggregationMethod *AggregationMethod `json:"aggregation_method,omitempty"`
+}
+
+// StrategyName returns the Watcher strategy name
+func (p VMWorkloadConsolidationParams) StrategyName() string { return StrategyVMWorkloadConsolidation }
+
+// GoalName returns the goal the strategy belongs to
+func (p VMWorkloadConsolidationParams) GoalName() string { return GoalServerConsolidation }
+
+// Validate checks the parameters
+func (p VMWorkloadConsolidationParams) Validate() error {
+	if err := nonNegative("period", float64(p.Period)); err != nil {
+		return err
+	}
+	return nonNegative("granularity", float64(p.Granularity))
+}
+
+// NewVMWorkloadConsolidationAudit builds an audit for the
+// vm_workload_consolidation strategy
+func NewVMWorkloadConsolidationAudit(p VMWorkloadConsolidationParams, opts *AuditOptions) (*watcherclient.Audit, error) {
+	return NewAudit(p, opts)
+}
+
+// Host choices for the node_resource_consolidation strategy
+const (
+	HostChoiceAuto    = "auto"
+	HostChoiceSpecify = "specify"
+)
+
+// NodeResourceConsolidationParams represents the parameters of the
+// node_resource_consolidation strategy
+type NodeResourceConsolidationParams struct {
+	HostChoice string `json:"host_choice,omitempty"` // auto, specify
+}
+
+// StrategyName returns the Watcher strategy name
+func (p NodeResourceConsolidationParams) StrategyName() string {
+	return StrategyNodeResourceConsolidation
+}
+
+// GoalName returns the goal the strategy belongs to
+func (p NodeResourceConsolidationParams) GoalName() string { return GoalServerConsolidation }
+
+// Validate checks the parameters
+func (p NodeResourceConsolidationParams) Validate() error {
+	return oneOf("host_choice", p.HostChoice, HostChoiceAuto, HostChoiceSpecify)
+}
+
+// NewNodeResourceConsolidationAudit builds an audit for the
+// node_resource_consolidation strategy
+func NewNodeResourceConsolidationAudit(p NodeResourceConsolidationParams, opts *AuditOptions) (*watcherclient.Audit, error) {
+	return NewAudit(p, opts)
+}
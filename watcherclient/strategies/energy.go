@@ -0,0 +1,64 @@
+package strategies
+
+import (
+	"fmt"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+)
+
+// Strategy names for the saving_energy and noisy_neighbor goals
+const (
+	StrategySavingEnergy  = "saving_energy"
+	StrategyNoisyNeighbor = "noisy_neighbor"
+)
+
+// SavingEnergyParams represents the parameters of the saving_energy strategy
+type SavingEnergyParams struct {
+	FreeUsedPercent float64 `json:"free_used_percent,omitempty"`
+	MinFreeHostsNum int     `json:"min_free_hosts_num,omitempty"`
+}
+
+// StrategyName returns the Watcher strategy name
+func (p SavingEnergyParams) StrategyName() string { return StrategySavingEnergy }
+
+// GoalName returns the goal the strategy belongs to
+func (p SavingEnergyParams) GoalName() string { return GoalSavingEnergy }
+
+// Validate checks the parameters
+func (p SavingEnergyParams) Validate() error {
+	if p.FreeUsedPercent < 0 || p.FreeUsedPercent > 100 {
+		return fmt.Errorf("free_used_percent must be between 0 and 100")
+	}
+	return nonNegative("min_free_hosts_num", float64(p.MinFreeHostsNum))
+}
+
+// NewSavingEnergyAudit builds an audit for the saving_energy strategy
+func NewSavingEnergyAudit(p SavingEnergyParams, opts *AuditOptions) (*watcherclient.Audit, error) {
+	return NewAudit(p, opts)
+}
+
+// NoisyNeighborParams represents the parameters of the noisy_neighbor
+// strategy
+type NoisyNeighborParams struct {
+	CacheThreshold float64 `json:"cache_threshold,omitempty"` // Percent
+	Period         int     `json:"period,omitempty"`          // Seconds
+}
+
+// StrategyName returns the Watcher strategy name
+func (p NoisyNeighborParams) StrategyName() string { return StrategyNoisyNeighbor }
+
+// GoalName returns the goal the strategy belongs to
+func (p NoisyNeighborParams) GoalName() string { return GoalNoisyNeighbor }
+
+// Validate checks the parameters
+func (p NoisyNeighborParams) Validate() error {
+	if p.CacheThreshold < 0 || p.CacheThreshold > 100 {
+		return fmt.Errorf("cache_threshold must be between 0 and 100")
+	}
+	return nonNegative("period", float64(p.Period))
+}
+
+// NewNoisyNeighborAudit builds an audit for the noisy_neighbor strategy
+func NewNoisyNeighborAudit(p NoisyNeighborParams, opts *AuditOptions) (*watcherclient.Audit, error) {
+	return NewAudit(p, opts)
+}
@@ -0,0 +1,115 @@
+package strategies
+
+import (
+	"fmt"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+)
+
+// Strategy names for the maintenance goals
+const (
+	StrategyHostMaintenance = "host_maintenance"
+	StrategyZoneMigration   = "zone_migration"
+)
+
+// HostMaintenanceParams represents the parameters of the host_maintenance
+// strategy
+type HostMaintenanceParams struct {
+	MaintenanceNode string `json:"maintenance_node"`
+	BackupNode      string `json:"backup_node,omitempty"`
+}
+
+// StrategyName returns the Watcher strategy name
+func (p HostMaintenanceParams) StrategyName() string { return StrategyHostMaintenance }
+
+// GoalName returns the goal the strategy belongs to
+func (p HostMaintenanceParams) GoalName() string { return GoalClusterMaintaining }
+
+// Validate checks the parameters
+func (p HostMaintenanceParams) Validate() error {
+	if p.MaintenanceNode == "" {
+		return fmt.Errorf("maintenance_node is required")
+	}
+	if p.BackupNode == p.MaintenanceNode {
+		return fmt.Errorf("backup_node must differ from maintenance_node")
+	}
+	return nil
+}
+
+// NewHostMaintenanceAudit builds an audit for the host_maintenance strategy
+func NewHostMaintenanceAudit(p HostMaintenanceParams, opts *AuditOptions) (*watcherclient.Audit, error) {
+	return NewAudit(p, opts)
+}
+
+// ComputeNodeMigration maps a source compute node to a destination
+type ComputeNodeMigration struct {
+	SrcNode string `json:"src_node"`
+	DstNode string `json:"dst_node,omitempty"`
+}
+
+// StoragePoolMigration maps a source storage pool to a destination
+type StoragePoolMigration struct {
+	SrcPool string `json:"src_pool"`
+	DstPool string `json:"dst_pool,omitempty"`
+	SrcType string `json:"src_type"`
+	DstType string `json:"dst_type"`
+}
+
+// ZoneMigrationPriority orders the migrated resources
+type ZoneMigrationPriority struct {
+	Project     []string `json:"project,omitempty"`
+	ComputeNode []string `json:"compute_node,omitempty"`
+	StoragePool []string `json:"storage_pool,omitempty"`
+	Compute     []string `json:"compute,omitempty"` // vcpu_num, mem_size, disk_size, created_at
+	Storage     []string `json:"storage,omitempty"` // size, created_at
+}
+
+// ZoneMigrationParams represents the parameters of the zone_migration
+// strategy
+type ZoneMigrationParams struct {
+	ComputeNodes       []ComputeNodeMigration `json:"compute_nodes,omitempty"`
+	StoragePools       []StoragePoolMigration `json:"storage_pools,omitempty"`
+	ParallelTotal      int                    `json:"parallel_total,omitempty"`
+	ParallelPerNode    int                    `json:"parallel_per_node,omitempty"`
+	ParallelPerPool    int                    `json:"parallel_per_pool,omitempty"`
+	Priority           *ZoneMigrationPriority `json:"priority,omitempty"`
+	WithAttachedVolume bool                   `json:"with_attached_volume,omitempty"`
+}
+
+// StrategyName returns the Watcher strategy name
+func (p ZoneMigrationParams) StrategyName() string { return StrategyZoneMigration }
+
+// GoalName returns the goal the strategy belongs to
+func (p ZoneMigrationParams) GoalName() string { return GoalHardwareMaintenance }
+
+// Validate checks the parameters
+func (p ZoneMigrationParams) Validate() error {
+	if len(p.ComputeNodes) == 0 && len(p.StoragePools) == 0 {
+		return fmt.Errorf("at least one of compute_nodes or storage_pools is required")
+	}
+
+	for i, node := range p.ComputeNodes {
+		if node.SrcNode == "" {
+			return fmt.Errorf("compute_nodes[%d]: src_node is required", i)
+		}
+	}
+
+	for i, pool := range p.StoragePools {
+		if pool.SrcPool == "" || pool.SrcType == "" || pool.DstType == "" {
+			return fmt.Errorf("storage_pools[%d]: src_pool, src_type and dst_type are required", i)
+		}
+	}
+
+	if err := nonNegative("parallel_total", float64(p.ParallelTotal)); err != nil {
+		return err
+	}
+	if err := nonNegative("parallel_per_node", float64(p.ParallelPerNode)); err != nil {
+		return err
+	}
+	return nonNegative("parallel_per_pool", float64(p.ParallelPerPool))
+}
+
+// NewZoneMigrationAudit builds an audit for the zone_migration strategy
+func NewZoneMigrationAudit(p ZoneMigrationParams, opts *AuditOptions) (*watcherclient.Audit, error) {
+	return NewAudit(p, opts)
+}
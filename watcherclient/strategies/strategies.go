@@ -0,0 +1,140 @@
+// Package strategies provides typed parameters for the upstream Watcher
+// strategies and builds ready-to-submit audits from them.
+package strategies
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+)
+
+// Goal names defined by upstream Watcher
+const (
+	GoalServerConsolidation = "server_consolidation"
+	GoalWorkloadBalancing   = "workload_balancing"
+	GoalThermalOptimization = "thermal_optimization"
+	GoalAirflowOptimization = "airflow_optimization"
+	GoalNoisyNeighbor       = "noisy_neighbor"
+	GoalClusterMaintaining  = "cluster_maintaining"
+	GoalHardwareMaintenance = "hardware_maintenance"
+	GoalSavingEnergy        = "saving_energy"
+)
+
+// Audit types accepted by Watcher
+const (
+	AuditTypeOneShot    = "ONESHOT"
+	AuditTypeContinuous = "CONTINUOUS"
+)
+
+// Params is implemented by the typed parameter set of every strategy.
+// Zero-valued fields are omitted so that Watcher applies its own defaults.
+type Params interface {
+	// StrategyName returns the Watcher strategy name
+	StrategyName() string
+	// GoalName returns the goal the strategy belongs to
+	GoalName() string
+	// Validate checks the parameters before they are sent to Watcher
+	Validate() error
+}
+
+// AuditOptions holds the audit fields that do not depend on the strategy
+type AuditOptions struct {
+	Name        string
+	AuditType   string // ONESHOT (default), CONTINUOUS
+	Interval    string // Required for CONTINUOUS audits
+	Scope       []map[string]interface{}
+	AutoTrigger bool
+}
+
+// AggregationMethod selects the aggregation function used per metric source
+type AggregationMethod struct {
+	Instance    string `json:"instance,omitempty"`
+	ComputeNode string `json:"compute_node,omitempty"`
+	Node        string `json:"node,omitempty"`
+}
+
+// NewAudit builds an audit for the given strategy parameters
+func NewAudit(p Params, opts *AuditOptions) (*watcherclient.Audit, error) {
+	if p == nil {
+		return nil, fmt.Errorf("strategy parameters cannot be nil")
+	}
+
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s parameters: %w", p.StrategyName(), err)
+	}
+
+	params, err := ToParameters(p)
+	if err != nil {
+		return nil, err
+	}
+
+	audit := &watcherclient.Audit{
+		AuditType:  AuditTypeOneShot,
+		Goal:       p.GoalName(),
+		Strategy:   p.StrategyName(),
+		Parameters: params,
+	}
+
+	if opts != nil {
+		audit.Name = opts.Name
+		audit.Interval = opts.Interval
+		audit.Scope = opts.Scope
+		audit.AutoTrigger = opts.AutoTrigger
+		if opts.AuditType != "" {
+			audit.AuditType = opts.AuditType
+		}
+	}
+
+	switch audit.AuditType {
+	case AuditTypeOneShot:
+	case AuditTypeContinuous:
+		if audit.Interval == "" {
+			return nil, fmt.Errorf("interval is required for %s audits", AuditTypeContinuous)
+		}
+	default:
+		return nil, fmt.Errorf("unknown audit type '%s'", audit.AuditType)
+	}
+
+	return audit, nil
+}
+
+// ToParameters converts typed strategy parameters into the map sent to Watcher
+func ToParameters(p Params) (map[string]interface{}, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s parameters: %w", p.StrategyName(), err)
+	}
+
+	params := map[string]interface{}{}
+	if err := json.Unmarshal(data, &params); err != nil {
+		return nil, fmt.Errorf("failed to convert %s parameters: %w", p.StrategyName(), err)
+	}
+
+	if len(params) == 0 {
+		return nil, nil
+	}
+
+	return params, nil
+}
+
+// oneOf checks that value is empty or one of the allowed values
+func oneOf(field, value string, allowed ...string) error {
+	if value == "" {
+		return nil
+	}
+	for _, a := range allowed {
+		if value == a {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s must be one of %v, got '%s'", field, allowed, value)
+}
+
+// nonNegative checks that a numeric parameter is not negative
+func nonNegative(field string, value float64) error {
+	if value < 0 {
+		return fmt.Errorf("%s cannot be negative", field)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package strategies
+
+import (
+	"testing"
+)
+
+// Test 1: Audit built from typed parameters
+func TestNewWorkloadStabilizationAudit(t *testing.T) {
+	audit, err := NewWorkloadStabilizationAudit(WorkloadStabilizationParams{
+		Metrics:    []string{MetricInstanceCPUUsage},
+		Thresholds: map[string]float64{MetricInstanceCPUUsage: 0.2},
+	}, &AuditOptions{Name: "stabilize", AutoTrigger: true})
+	if err != nil {
+		t.Fatalf("NewWorkloadStabilizationAudit failed: %v", err)
+	}
+
+	if audit.Goal != GoalWorkloadBalancing {
+		t.Errorf("Expected goal %s, got %s", GoalWorkloadBalancing, audit.Goal)
+	}
+
+	if audit.Strategy != StrategyWorkloadStabilization {
+		t.Errorf("Expected strategy %s, got %s", StrategyWorkloadStabilization, audit.Strategy)
+	}
+
+	if audit.AuditType != AuditTypeOneShot {
+		t.Errorf("Expected audit type %s, got %s", AuditTypeOneShot, audit.AuditType)
+	}
+
+	metrics, ok := audit.Parameters["metrics"].([]interface{})
+	if !ok || len(metrics) != 1 || metrics[0] != MetricInstanceCPUUsage {
+		t.Errorf("Unexpected metrics parameter: %v", audit.Parameters["metrics"])
+	}
+
+	if _, ok := audit.Parameters["host_choice"]; ok {
+		t.Error("Zero-valued host_choice should be omitted")
+	}
+}
+
+// Test 2: Empty parameters produce no parameter map
+func TestNewAuditWithDefaults(t *testing.T) {
+	audit, err := NewBasicAudit(BasicParams{}, nil)
+	if err != nil {
+		t.Fatalf("NewBasicAudit failed: %v", err)
+	}
+
+	if audit.Parameters != nil {
+		t.Errorf("Expected nil parameters, got %v", audit.Parameters)
+	}
+}
+
+// Test 3: Validation errors
+func TestValidation(t *testing.T) {
+	cases := map[string]Params{
+		"missing maintenance node": HostMaintenanceParams{},
+		"unknown metric":           WorkloadBalanceParams{Metrics: "disk"},
+		"threshold out of range":   WorkloadStabilizationParams{Thresholds: map[string]float64{"instance_cpu_usage": 2}},
+		"empty zone migration":     ZoneMigrationParams{},
+		"unknown host choice":      NodeResourceConsolidationParams{HostChoice: "random"},
+	}
+
+	for name, params := range cases {
+		if _, err := NewAudit(params, nil); err == nil {
+			t.Errorf("%s: expected validation error, got nil", name)
+		}
+	}
+}
+
+// Test 4: Continuous audits require an interval
+func TestContinuousAuditInterval(t *testing.T) {
+	params := SavingEnergyParams{FreeUsedPercent: 20}
+
+	if _, err := NewSavingEnergyAudit(params, &AuditOptions{AuditType: AuditTypeContinuous}); err == nil {
+		t.Error("Expected error for continuous audit without interval")
+	}
+
+	audit, err := NewSavingEnergyAudit(params, &AuditOptions{AuditType: AuditTypeContinuous, Interval: "3600"})
+	if err != nil {
+		t.Fatalf("NewSavingEnergyAudit failed: %v", err)
+	}
+
+	if audit.Interval != "3600" {
+		t.Errorf("Expected interval 3600, got %s", audit.Interval)
+	}
+}
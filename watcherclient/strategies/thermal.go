@@ -0,0 +1,74 @@
+package strategies
+
+import (
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+)
+
+// Strategy names for the thermal and airflow optimization goals
+const (
+	StrategyOutletTemperature = "outlet_temperature"
+	StrategyUniformAirflow    = "uniform_airflow"
+)
+
+// OutletTemperatureParams represents the parameters of the
+// outlet_temperature strategy
+type OutletTemperatureParams struct {
+	Threshold   float64 `json:"threshold,omitempty"` // Celsius
+	Period      int     `json:"period,omitempty"`    // Seconds
+	Granularity int     `json:"granularity,omitempty"`
+}
+
+// StrategyName returns the Watcher strategy name
+func (p OutletTemperatureParams) StrategyName() string { return StrategyOutletTemperature }
+
+// GoalName returns the goal the strategy belongs to
+func (p OutletTemperatureParams) GoalName() string { return GoalThermalOptimization }
+
+// Validate checks the parameters
+func (p OutletTemperatureParams) Validate() error {
+	if err := nonNegative("period", float64(p.Period)); err != nil {
+		return err
+	}
+	return nonNegative("granularity", float64(p.Granularity))
+}
+
+// NewOutletTemperatureAudit builds an audit for the outlet_temperature
+// strategy
+func NewOutletTemperatureAudit(p OutletTemperatureParams, opts *AuditOptions) (*watcherclient.Audit, error) {
+	return NewAudit(p, opts)
+}
+
+// UniformAirflowParams represents the parameters of the uniform_airflow
+// strategy
+type UniformAirflowParams struct {
+	ThresholdAirflow float64 `json:"threshold_airflow,omitempty"` // 0.1 CFM
+	ThresholdInletT  float64 `json:"threshold_inlet_t,omitempty"` // Celsius
+	ThresholdPower   float64 `json:"threshold_power,omitempty"`   // Watts
+	Period           int     `json:"period,omitempty"`            // Seconds
+	Granularity      int     `json:"granularity,omitempty"`
+}
+
+// StrategyName returns the Watcher strategy name
+func (p UniformAirflowParams) StrategyName() string { return StrategyUniformAirflow }
+
+// GoalName returns the goal the strategy belongs to
+func (p UniformAirflowParams) GoalName() string { return GoalAirflowOptimization }
+
+// Validate checks the parameters
+func (p UniformAirflowParams) Validate() error {
+	if err := nonNegative("threshold_airflow", p.ThresholdAirflow); err != nil {
+		return err
+	}
+	if err := nonNegative("threshold_power", p.ThresholdPower); err != nil {
+		return err
+	}
+	if err := nonNegative("period", float64(p.Period)); err != nil {
+		return err
+	}
+	return nonNegative("granularity", float64(p.Granularity))
+}
+
+// NewUniformAirflowAudit builds an audit for the uniform_airflow strategy
+func NewUniformAirflowAudit(p UniformAirflowParams, opts *AuditOptions) (*watcherclient.Audit, error) {
+	return NewAudit(p, opts)
+}
@@ -0,0 +1,233 @@
+package watcherclient
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultTokenRefreshMargin is the remaining lifetime below which a token is
+// renewed instead of used
+const DefaultTokenRefreshMargin = 5 * time.Minute
+
+// ErrTokenNotCached is returned by a TokenCache without an entry for a key
+var ErrTokenNotCached = errors.New("token not cached")
+
+// CachedToken is a Keystone token saved for reuse by later processes
+type CachedToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+	IssuedAt  time.Time `json:"issued_at,omitempty"`
+	Endpoint  string    `json:"endpoint"` // Watcher endpoint found in the catalog
+	UserID    string    `json:"user_id,omitempty"`
+	ProjectID string    `json:"project_id,omitempty"`
+	Roles     []string  `json:"roles,omitempty"`
+}
+
+// TokenCache stores tokens across processes. Keys identify the auth URL,
+// user, credentials, scope and endpoint selection, and contain digests of
+// secrets rather than the secrets. Tokens of a TokenProvider or obtained
+// with a passcode are not cached.
+type TokenCache interface {
+	Load(key string) (*CachedToken, error)
+	Store(key string, token CachedToken) error
+}
+
+// FileTokenCache stores one file per key in a directory only readable by
+// the current user. Tokens are encrypted with AES-GCM when Key is set.
+type FileTokenCache struct {
+	Dir string
+	Key []byte // AES key of 16, 24 or 32 bytes, see TokenCacheKey
+}
+
+// NewFileTokenCache returns a cache in dir, or in DefaultTokenCacheDir when
+// dir is empty. A non-empty passphrase enables encryption.
+func NewFileTokenCache(dir, passphrase string) (*FileTokenCache, error) {
+	if dir == "" {
+		var err error
+		if dir, err = DefaultTokenCacheDir(); err != nil {
+			return nil, err
+		}
+	}
+
+	cache := &FileTokenCache{Dir: expandHome(dir)}
+	if passphrase != "" {
+		cache.Key = TokenCacheKey(passphrase)
+	}
+	return cache, nil
+}
+
+// DefaultTokenCacheDir returns the token cache directory below the user
+// cache directory
+func DefaultTokenCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate cache directory: %w", err)
+	}
+	return filepath.Join(dir, "watcherclient", "tokens"), nil
+}
+
+// TokenCacheKey derives an AES-256 key from a passphrase
+func TokenCacheKey(passphrase string) []byte {
+	sum := sha256.Sum256([]byte(passphrase))
+	return sum[:]
+}
+
+// Load reads the token stored for key. Files readable by other users are
+// rejected.
+func (c *FileTokenCache) Load(key string) (*CachedToken, error) {
+	path := c.path(key)
+
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrTokenNotCached
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat token cache: %w", err)
+	}
+	if info.Mode().Perm()&0o077 != 0 {
+		return nil, fmt.Errorf("token cache %s is accessible by other users", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token cache: %w", err)
+	}
+
+	if c.Key != nil {
+		if data, err = c.open(data); err != nil {
+			return nil, err
+		}
+	}
+
+	var token CachedToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("failed to parse token cache: %w", err)
+	}
+	return &token, nil
+}
+
+// Store writes the token for key, replacing the file atomically
+func (c *FileTokenCache) Store(key string, token CachedToken) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("failed to marshal token: %w", err)
+	}
+
+	if c.Key != nil {
+		if data, err = c.seal(data); err != nil {
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(c.Dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create token cache directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(c.Dir, ".token-*")
+	if err != nil {
+		return fmt.Errorf("failed to write token cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write token cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write token cache: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), c.path(key)); err != nil {
+		return fmt.Errorf("failed to write token cache: %w", err)
+	}
+	return nil
+}
+
+// path returns the file of a key
+func (c *FileTokenCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// seal encrypts data, prefixed with its nonce
+func (c *FileTokenCache) seal(data []byte) ([]byte, error) {
+	gcm, err := c.cipher()
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return gcm.Seal(nonce, nonce, data, nil), nil
+}
+
+// open decrypts data produced by seal
+func (c *FileTokenCache) open(data []byte) ([]byte, error) {
+	gcm, err := c.cipher()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return nil, fmt.Errorf("token cache is truncated")
+	}
+	nonce, sealed := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+
+	plain, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt token cache: %w", err)
+	}
+	return plain, nil
+}
+
+// cipher returns the AES-GCM cipher of the cache key
+func (c *FileTokenCache) cipher() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(c.Key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token cache key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// tokenCacheKey identifies the tokens interchangeable with those obtained
+// with opts
+func tokenCacheKey(opts *AuthOptions) string {
+	fields := []string{
+		opts.IdentityEndpoint,
+		opts.UserID, opts.Username, opts.DomainID, opts.DomainName,
+		opts.ApplicationCredentialID, opts.ApplicationCredentialName,
+		opts.TenantID, opts.TenantName,
+		opts.Region, opts.Interface, opts.ServiceName, opts.EndpointOverride,
+	}
+	if opts.TokenID != "" {
+		fields = append(fields, "token:"+digest(opts.TokenID))
+	}
+	// Application credentials sharing a name are told apart by their secret
+	if opts.ApplicationCredentialSecret != "" {
+		fields = append(fields, "secret:"+digest(opts.ApplicationCredentialSecret))
+	}
+	if scope := opts.Scope; scope != nil {
+		fields = append(fields, scope.ProjectID, scope.ProjectName, scope.DomainID, scope.DomainName,
+			fmt.Sprint(scope.System), scope.TrustID)
+	}
+	return strings.Join(fields, "\x00")
+}
+
+// digest returns the hex encoded SHA-256 of a secret
+func digest(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,74 @@
+package watcherclient_test
+
+import (
+	"testing"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+	"github.com/overwatch144/golang-watcherclient/watcherclient/watchertest"
+)
+
+// Test 1: Tokens shared through the token cache
+func TestTokenCache(t *testing.T) {
+	server := watchertest.NewServer(watchertest.Options{Username: "admin", Password: "secret"})
+	defer server.Close()
+
+	cache, err := watcherclient.NewFileTokenCache(t.TempDir(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := server.ClientOptions()
+	opts.TokenCache = cache
+	first, err := watcherclient.NewClient(opts)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	// A later process finds the token without authenticating
+	opts.Password = "wrong"
+	second, err := watcherclient.NewClient(opts)
+	if err != nil {
+		t.Fatalf("Expected cached token, got %v", err)
+	}
+	if second.GetEndpoint() != first.GetEndpoint() {
+		t.Errorf("Expected endpoint %s, got %s", first.GetEndpoint(), second.GetEndpoint())
+	}
+	if _, err := second.ListGoals(nil); err != nil {
+		t.Errorf("ListGoals with cached token failed: %v", err)
+	}
+
+	// Tokens of another project are not shared
+	opts.ProjectName = "demo"
+	if _, err := watcherclient.NewClient(opts); err == nil {
+		t.Error("Expected authentication for another project")
+	}
+}
+
+// Test 2: Tokens of a provider or a passcode are not cached
+func TestTokenCacheSkipped(t *testing.T) {
+	server := watchertest.NewServer(watchertest.Options{Username: "admin", Password: "secret", Passcode: "123456"})
+	defer server.Close()
+
+	cache := &recordingCache{}
+	opts := server.ClientOptions()
+	opts.Passcode = "123456"
+	opts.TokenCache = cache
+	if _, err := watcherclient.NewClient(opts); err != nil {
+		t.Fatalf("NewClient with passcode failed: %v", err)
+	}
+
+	_, err := watcherclient.NewClient(watcherclient.ClientOptions{
+		AuthURL:         server.AuthURL(),
+		ProjectName:     "admin",
+		ProjectDomainID: "default",
+		TokenProvider:   func() (string, error) { return server.Token(), nil },
+		TokenCache:      cache,
+	})
+	if err != nil {
+		t.Fatalf("NewClient with token provider failed: %v", err)
+	}
+
+	if cache.stored != 0 {
+		t.Errorf("Expected no cached tokens, got %d", cache.stored)
+	}
+}
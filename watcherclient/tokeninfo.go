@@ -0,0 +1,94 @@
+package watcherclient
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/openstack/identity/v3/tokens"
+)
+
+// CatalogEntry is a service of the Keystone catalog
+type CatalogEntry struct {
+	ID        string
+	Type      string
+	Name      string
+	Endpoints []CatalogEndpoint
+}
+
+// CatalogEndpoint is an endpoint of a catalog service
+type CatalogEndpoint struct {
+	Region    string
+	Interface string
+	URL       string
+}
+
+// tokenIdentity is what Keystone reported about a token
+type tokenIdentity struct {
+	ExpiresAt      time.Time
+	IssuedAt       time.Time
+	UserID         string
+	Username       string
+	UserDomainID   string
+	UserDomainName string
+	ProjectID      string
+	ProjectName    string
+	Roles          []string
+	Catalog        []CatalogEntry
+}
+
+// tokenResult is implemented by tokens.CreateResult and, for unscoped
+// token authentication, by tokens.GetResult
+type tokenResult interface {
+	ExtractInto(v interface{}) error
+}
+
+// identityFromResult reads the token details of an authentication
+// response, avoiding a second request to Keystone
+func identityFromResult(result gophercloud.AuthResult) (tokenIdentity, error) {
+	body, ok := result.(tokenResult)
+	if !ok {
+		return tokenIdentity{}, fmt.Errorf("unexpected authentication result %T", result)
+	}
+
+	var token struct {
+		ExpiresAt time.Time             `json:"expires_at"`
+		IssuedAt  time.Time             `json:"issued_at"`
+		User      tokens.User           `json:"user"`
+		Project   *tokens.Project       `json:"project"`
+		Roles     []tokens.Role         `json:"roles"`
+		Catalog   []tokens.CatalogEntry `json:"catalog"`
+	}
+	if err := body.ExtractInto(&token); err != nil {
+		return tokenIdentity{}, fmt.Errorf("failed to parse token: %w", err)
+	}
+
+	identity := tokenIdentity{
+		ExpiresAt:      token.ExpiresAt,
+		IssuedAt:       token.IssuedAt,
+		UserID:         token.User.ID,
+		Username:       token.User.Name,
+		UserDomainID:   token.User.Domain.ID,
+		UserDomainName: token.User.Domain.Name,
+	}
+	if token.Project != nil {
+		identity.ProjectID = token.Project.ID
+		identity.ProjectName = token.Project.Name
+	}
+	for _, role := range token.Roles {
+		identity.Roles = append(identity.Roles, role.Name)
+	}
+	for _, service := range token.Catalog {
+		entry := CatalogEntry{ID: service.ID, Type: service.Type, Name: service.Name}
+		for _, endpoint := range service.Endpoints {
+			entry.Endpoints = append(entry.Endpoints, CatalogEndpoint{
+				Region:    endpoint.Region,
+				Interface: endpoint.Interface,
+				URL:       endpoint.URL,
+			})
+		}
+		identity.Catalog = append(identity.Catalog, entry)
+	}
+
+	return identity, nil
+}
@@ -0,0 +1,125 @@
+package watcherclient_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+	"github.com/overwatch144/golang-watcherclient/watcherclient/watchertest"
+)
+
+// Test 1: Token details taken from the authentication response
+func TestAuthInfoFromToken(t *testing.T) {
+	server := watchertest.NewServer(watchertest.Options{Roles: []string{"admin", "reader"}})
+	defer server.Close()
+
+	transport := &blockingTransport{release: make(chan struct{})}
+	authOpts := watcherclient.BuildAuthOptions(server.ClientOptions())
+	authOpts.Transport = transport
+	var warnings []string
+	authOpts.OnWarning = func(message string) { warnings = append(warnings, message) }
+
+	auth, err := watcherclient.NewAuthenticator(authOpts)
+	if err != nil {
+		t.Fatalf("NewAuthenticator failed: %v", err)
+	}
+	if transport.posts != 1 || transport.gets != 0 {
+		t.Errorf("Expected a single Keystone request, got %d POST and %d GET", transport.posts, transport.gets)
+	}
+
+	info := auth.GetAuthInfo()
+	if info.TokenExpiry.IsZero() || info.IssuedAt.IsZero() || !info.IssuedAt.Before(info.TokenExpiry) {
+		t.Errorf("Expected issued_at before expires_at, got %v and %v", info.IssuedAt, info.TokenExpiry)
+	}
+	if len(info.Roles) != 2 || info.Roles[0] != "admin" || info.Roles[1] != "reader" {
+		t.Errorf("Expected roles admin and reader, got %v", info.Roles)
+	}
+	if info.ProjectID == "" || info.UserID == "" {
+		t.Errorf("Expected project and user IDs, got %+v", info)
+	}
+	if len(info.Catalog) == 0 || info.Catalog[0].Type != "infra-optim" || len(info.Catalog[0].Endpoints) == 0 {
+		t.Errorf("Expected infra-optim catalog entry, got %+v", info.Catalog)
+	}
+	if info.Warning != "" || len(warnings) != 0 {
+		t.Errorf("Expected no warning, got %q %v", info.Warning, warnings)
+	}
+}
+
+// Test 2: Token details of an unscoped token
+func TestAuthInfoUnscopedToken(t *testing.T) {
+	server := watchertest.NewServer(watchertest.Options{Roles: []string{"reader"}})
+	defer server.Close()
+
+	var warnings []string
+	client, err := watcherclient.NewClient(watcherclient.ClientOptions{
+		AuthURL:   server.AuthURL(),
+		Token:     server.Token(),
+		OnWarning: func(message string) { warnings = append(warnings, message) },
+	})
+	if err != nil {
+		t.Fatalf("NewClient with unscoped token failed: %v", err)
+	}
+
+	info, err := client.GetAuthInfo()
+	if err != nil {
+		t.Fatalf("GetAuthInfo failed: %v", err)
+	}
+	if info.TokenExpiry.IsZero() || info.IssuedAt.IsZero() {
+		t.Errorf("Expected issued_at and expires_at, got %v and %v", info.IssuedAt, info.TokenExpiry)
+	}
+	if len(info.Roles) != 1 || info.Roles[0] != "reader" || len(info.Catalog) == 0 {
+		t.Errorf("Expected role reader and a catalog, got %v %+v", info.Roles, info.Catalog)
+	}
+	if info.Warning != "" || len(warnings) != 0 {
+		t.Errorf("Expected no warning, got %q %v", info.Warning, warnings)
+	}
+}
+
+// recordingCache is a TokenCache counting stored tokens
+type recordingCache struct {
+	stored int
+}
+
+func (c *recordingCache) Load(key string) (*watcherclient.CachedToken, error) {
+	return nil, watcherclient.ErrTokenNotCached
+}
+
+func (c *recordingCache) Store(key string, token watcherclient.CachedToken) error {
+	c.stored++
+	return nil
+}
+
+// Test 3: Warning and no caching when Keystone omits the token expiry
+func TestUnknownTokenExpiry(t *testing.T) {
+	server := watchertest.NewServer(watchertest.Options{OmitTokenExpiry: true})
+	defer server.Close()
+
+	cache := &recordingCache{}
+	var warnings []string
+	opts := server.ClientOptions()
+	opts.TokenCache = cache
+	opts.OnWarning = func(message string) { warnings = append(warnings, message) }
+
+	client, err := watcherclient.NewClient(opts)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	info, err := client.GetAuthInfo()
+	if err != nil {
+		t.Fatalf("GetAuthInfo failed: %v", err)
+	}
+	if !info.TokenExpiry.IsZero() {
+		t.Errorf("Expected unknown expiry, got %v", info.TokenExpiry)
+	}
+	if !strings.Contains(info.Warning, "expiry unknown") || len(warnings) != 1 || warnings[0] != info.Warning {
+		t.Errorf("Expected an expiry warning, got %q %v", info.Warning, warnings)
+	}
+	if cache.stored != 0 {
+		t.Errorf("Expected no cached token, got %d", cache.stored)
+	}
+
+	if _, err := client.ListGoals(nil); err != nil {
+		t.Errorf("ListGoals failed: %v", err)
+	}
+}
@@ -0,0 +1,197 @@
+package watcherclient
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// NewTransport returns the transport configured by the TLS and proxy
+// options, shared by Keystone and Watcher requests, or nil to use
+// http.DefaultTransport
+func NewTransport(opts ClientOptions) (http.RoundTripper, error) {
+	if opts.CACert == "" && opts.ClientCert == "" && opts.ClientKey == "" && !opts.Insecure && opts.Proxy == "" {
+		return nil, nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if opts.Proxy != "" {
+		proxy, err := url.Parse(opts.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	tlsConfig, err := newTLSConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	return transport, nil
+}
+
+// newTLSConfig builds the TLS configuration of the client. The CA bundle
+// and the client certificate are reloaded when their files change, so that
+// rotated certificates are used by new connections without restarting.
+func newTLSConfig(opts ClientOptions) (*tls.Config, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if opts.Insecure {
+		config.InsecureSkipVerify = true
+	} else if opts.CACert != "" {
+		cas := &reloadingFile{path: expandHome(opts.CACert), load: loadCertPool}
+		if _, err := cas.get(); err != nil {
+			return nil, err
+		}
+
+		// Verification is done in VerifyConnection against the current
+		// bundle instead of a fixed RootCAs pool
+		config.InsecureSkipVerify = true
+		config.VerifyConnection = func(cs tls.ConnectionState) error {
+			pool, err := cas.get()
+			if err != nil {
+				return err
+			}
+			return verifyPeer(cs, pool.(*x509.CertPool))
+		}
+	}
+
+	if opts.ClientCert != "" || opts.ClientKey != "" {
+		if opts.ClientCert == "" || opts.ClientKey == "" {
+			return nil, fmt.Errorf("client certificate and key must be set together")
+		}
+
+		keyPath := expandHome(opts.ClientKey)
+		cert := &reloadingFile{
+			path: expandHome(opts.ClientCert),
+			deps: []string{keyPath},
+			load: func(certPath string) (interface{}, error) {
+				pair, err := tls.LoadX509KeyPair(certPath, keyPath)
+				if err != nil {
+					return nil, fmt.Errorf("failed to load client certificate: %w", err)
+				}
+				return &pair, nil
+			},
+		}
+		if _, err := cert.get(); err != nil {
+			return nil, err
+		}
+
+		config.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			pair, err := cert.get()
+			if err != nil {
+				return nil, err
+			}
+			return pair.(*tls.Certificate), nil
+		}
+	}
+
+	return config, nil
+}
+
+// verifyPeer verifies the server certificate chain and host name
+func verifyPeer(cs tls.ConnectionState, roots *x509.CertPool) error {
+	if len(cs.PeerCertificates) == 0 {
+		return fmt.Errorf("server presented no certificate")
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range cs.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+		DNSName:       cs.ServerName,
+		Roots:         roots,
+		Intermediates: intermediates,
+	})
+	return err
+}
+
+// reloadingFile caches a value loaded from files and reloads it when the
+// modification time or size of one of them changes. The previous value is
+// kept if a reload fails, e.g. while the file is being rewritten.
+type reloadingFile struct {
+	path  string
+	deps  []string // Additional files triggering a reload
+	load  func(path string) (interface{}, error)
+	mutex sync.Mutex
+	stamp string
+	value interface{}
+}
+
+// get returns the current value, reloading it if the files changed
+func (r *reloadingFile) get() (interface{}, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	stamp, err := fileStamp(append([]string{r.path}, r.deps...)...)
+	if err != nil {
+		if r.value != nil {
+			return r.value, nil
+		}
+		return nil, err
+	}
+	if stamp == r.stamp && r.value != nil {
+		return r.value, nil
+	}
+
+	value, err := r.load(r.path)
+	if err != nil {
+		if r.value != nil {
+			return r.value, nil
+		}
+		return nil, err
+	}
+
+	r.value = value
+	r.stamp = stamp
+	return value, nil
+}
+
+// fileStamp summarizes the modification time and size of files
+func fileStamp(paths ...string) (string, error) {
+	var b strings.Builder
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to stat %s: %w", path, err)
+		}
+		fmt.Fprintf(&b, "%s:%d:%d;", path, info.ModTime().UnixNano(), info.Size())
+	}
+	return b.String(), nil
+}
+
+// loadCertPool reads a PEM bundle of CA certificates
+func loadCertPool(path string) (interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(bytes.TrimSpace(data)) {
+		return nil, fmt.Errorf("no CA certificate found in %s", path)
+	}
+	return pool, nil
+}
+
+// expandHome replaces a leading "~/" with the user's home directory
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return path
+}
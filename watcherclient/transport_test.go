@@ -0,0 +1,91 @@
+package watcherclient_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+	"github.com/overwatch144/golang-watcherclient/watcherclient/watchertest"
+)
+
+// Test 1: TLS verification, insecure mode and CA bundle rotation
+func TestTLS(t *testing.T) {
+	server := watchertest.NewServer(watchertest.Options{TLS: true})
+	defer server.Close()
+
+	if _, err := watcherclient.NewClient(server.ClientOptions()); err == nil {
+		t.Error("Expected error for an untrusted certificate")
+	}
+
+	insecure := server.ClientOptions()
+	insecure.Insecure = true
+	if _, err := watcherclient.NewClient(insecure); err != nil {
+		t.Errorf("Expected insecure client, got %v", err)
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	writeCA := func(data []byte, stamp time.Time) {
+		if err := os.WriteFile(caFile, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(caFile, stamp, stamp); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeCA(server.CACertPEM(), time.Now().Add(-time.Hour))
+
+	opts := server.ClientOptions()
+	opts.CACert = caFile
+	client, err := watcherclient.NewClient(opts)
+	if err != nil {
+		t.Fatalf("NewClient with CA failed: %v", err)
+	}
+	if _, err := client.ListGoals(nil); err != nil {
+		t.Errorf("ListGoals failed: %v", err)
+	}
+
+	// A rotated bundle applies to new connections
+	idle := client.GetTransport().(interface{ CloseIdleConnections() })
+	writeCA(otherCA(t), time.Now().Add(-time.Minute))
+	idle.CloseIdleConnections()
+	if _, err := client.ListGoals(nil); err == nil {
+		t.Error("Expected error after rotating to another CA")
+	}
+
+	writeCA(server.CACertPEM(), time.Now())
+	idle.CloseIdleConnections()
+	if _, err := client.ListGoals(nil); err != nil {
+		t.Errorf("Expected success after restoring the CA, got %v", err)
+	}
+}
+
+// otherCA returns a self-signed certificate unrelated to the server
+func otherCA(t *testing.T) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "other CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
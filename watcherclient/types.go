@@ -103,10 +103,21 @@ type StrategyParameter struct {
 	Required    bool        `json:"required"`
 }
 
+// Service represents a Watcher service (API, decision engine or applier)
+type Service struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	Host       string `json:"host"`
+	Status     string `json:"status"` // ACTIVE, FAILED
+	LastSeenUp string `json:"last_seen_up,omitempty"`
+	Links      []Link `json:"links,omitempty"`
+}
+
 // DataModel represents the infrastructure data model
 type DataModel struct {
-	Type string                 `json:"type"`
-	Data map[string]interface{} `json:"data"`
+	Type    string                   `json:"type"`
+	Data    map[string]interface{}   `json:"data"`
+	Context []map[string]interface{} `json:"context,omitempty"` // Flattened elements as returned by Watcher
 }
 
 // Link represents a HATEOAS link
@@ -147,3 +158,8 @@ type GoalsResponse struct {
 type StrategiesResponse struct {
 	Strategies []Strategy `json:"strategies"`
 }
+
+// ServicesResponse is the body of a service list response
+type ServicesResponse struct {
+	Services []Service `json:"services"`
+}
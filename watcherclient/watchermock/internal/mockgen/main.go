@@ -0,0 +1,113 @@
+// Command mockgen generates the watchermock.Mock methods from the
+// watcherclient.API interface. Run it with go generate in the watchermock
+// directory after changing the interface.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+)
+
+func main() {
+	output := flag.String("o", "mock_gen.go", "output file")
+	flag.Parse()
+
+	src, err := generate(reflect.TypeOf((*watcherclient.API)(nil)).Elem())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, src, 0644); err != nil {
+		log.Fatalf("failed to write %s: %v", *output, err)
+	}
+}
+
+// generate returns the formatted source of the mock of iface
+func generate(iface reflect.Type) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by mockgen. DO NOT EDIT.\n\n")
+	b.WriteString("package watchermock\n\n")
+	b.WriteString("import \"github.com/overwatch144/golang-watcherclient/watcherclient\"\n\n")
+
+	b.WriteString("// Mock is a configurable implementation of watcherclient.API. A call\n")
+	b.WriteString("// returns the results of the first matching expectation, then the result\n")
+	b.WriteString("// of the method's Func field when set, and zero values otherwise.\n")
+	b.WriteString("type Mock struct {\n\tRecorder\n\n")
+	for i := 0; i < iface.NumMethod(); i++ {
+		m := iface.Method(i)
+		fmt.Fprintf(&b, "\t%sFunc func%s\n", m.Name, signature(m.Type))
+	}
+	b.WriteString("}\n\nvar _ watcherclient.API = (*Mock)(nil)\n")
+
+	for i := 0; i < iface.NumMethod(); i++ {
+		writeMethod(&b, iface.Method(i))
+	}
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w", err)
+	}
+	return src, nil
+}
+
+// writeMethod writes the mock implementation of a method
+func writeMethod(b *bytes.Buffer, m reflect.Method) {
+	var params, args []string
+	for i := 0; i < m.Type.NumIn(); i++ {
+		name := fmt.Sprintf("a%d", i)
+		params = append(params, name+" "+typeName(m.Type.In(i)))
+		args = append(args, name)
+	}
+
+	fmt.Fprintf(b, "\n// %s records the call and returns the configured results\n", m.Name)
+	fmt.Fprintf(b, "func (m *Mock) %s(%s) %s {\n", m.Name, strings.Join(params, ", "), results(m.Type))
+	fmt.Fprintf(b, "\tresults, ok := m.called(%q, m.%sFunc != nil%s)\n", m.Name, m.Name, prefixed(args))
+	fmt.Fprintf(b, "\tif !ok && m.%sFunc != nil {\n\t\treturn m.%sFunc(%s)\n\t}\n", m.Name, m.Name, strings.Join(args, ", "))
+
+	var values []string
+	for i := 0; i < m.Type.NumOut(); i++ {
+		values = append(values, fmt.Sprintf("result[%s](results, %d)", typeName(m.Type.Out(i)), i))
+	}
+	fmt.Fprintf(b, "\treturn %s\n}\n", strings.Join(values, ", "))
+}
+
+// signature returns the parameter and result lists of a function type
+func signature(t reflect.Type) string {
+	var params []string
+	for i := 0; i < t.NumIn(); i++ {
+		params = append(params, typeName(t.In(i)))
+	}
+	return "(" + strings.Join(params, ", ") + ") " + results(t)
+}
+
+// results returns the result list of a function type
+func results(t reflect.Type) string {
+	var out []string
+	for i := 0; i < t.NumOut(); i++ {
+		out = append(out, typeName(t.Out(i)))
+	}
+	if len(out) == 1 {
+		return out[0]
+	}
+	return "(" + strings.Join(out, ", ") + ")"
+}
+
+// typeName returns the Go source name of a type
+func typeName(t reflect.Type) string {
+	return strings.ReplaceAll(t.String(), "interface {}", "interface{}")
+}
+
+// prefixed joins args with a leading separator
+func prefixed(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return ", " + strings.Join(args, ", ")
+}
@@ -0,0 +1,211 @@
+// Package watchermock provides a configurable mock of watcherclient.API with
+// call recording and expectations.
+//
+// Example use:
+//
+//	m := &watchermock.Mock{}
+//	m.On("GetAudit", "uuid-1").Return(&watcherclient.Audit{UUID: "uuid-1"}, nil).Once()
+//	m.ListGoalsFunc = func(opts *watcherclient.ListOptions) ([]watcherclient.Goal, error) {
+//		return nil, &watcherclient.APIError{StatusCode: 503}
+//	}
+//
+//	service := NewService(m) // accepts watcherclient.API
+//	...
+//	m.AssertExpectations(t)
+package watchermock
+
+//go:generate go run ./internal/mockgen -o mock_gen.go
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Any matches any argument value in an expectation
+var Any = anyArg{}
+
+type anyArg struct{}
+
+// TestingT is the subset of *testing.T used for assertions
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// Call records a method invocation
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// Expectation describes an expected call and the values it returns
+type Expectation struct {
+	method  string
+	args    []interface{}
+	results []interface{}
+	times   int // 0 allows any number of calls
+	calls   int
+}
+
+// Return sets the values returned by matching calls
+func (e *Expectation) Return(results ...interface{}) *Expectation {
+	e.results = results
+	return e
+}
+
+// Times limits the expectation to n calls
+func (e *Expectation) Times(n int) *Expectation {
+	e.times = n
+	return e
+}
+
+// Once limits the expectation to a single call
+func (e *Expectation) Once() *Expectation {
+	return e.Times(1)
+}
+
+// matches reports whether the expectation accepts the call
+func (e *Expectation) matches(method string, args []interface{}) bool {
+	if e.method != method {
+		return false
+	}
+	if e.times > 0 && e.calls >= e.times {
+		return false
+	}
+	if e.args == nil {
+		return true
+	}
+	if len(e.args) != len(args) {
+		return false
+	}
+	for i, want := range e.args {
+		if want == Any {
+			continue
+		}
+		if !reflect.DeepEqual(want, args[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Recorder records calls and matches them against expectations. It is
+// embedded in Mock.
+type Recorder struct {
+	// Strict reports calls without a matching expectation or function as
+	// failures in AssertExpectations
+	Strict bool
+
+	mutex        sync.Mutex
+	calls        []Call
+	expectations []*Expectation
+	unexpected   []Call
+}
+
+// On registers an expectation for method. Without arguments the
+// expectation matches any arguments; use Any to match a single argument.
+func (r *Recorder) On(method string, args ...interface{}) *Expectation {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	e := &Expectation{method: method}
+	if len(args) > 0 {
+		e.args = args
+	}
+	r.expectations = append(r.expectations, e)
+	return e
+}
+
+// Calls returns every recorded call
+func (r *Recorder) Calls() []Call {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// CallsTo returns the recorded calls to method
+func (r *Recorder) CallsTo(method string) []Call {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var calls []Call
+	for _, c := range r.calls {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// Reset clears the recorded calls and expectations
+func (r *Recorder) Reset() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.calls = nil
+	r.expectations = nil
+	r.unexpected = nil
+}
+
+// AssertExpectations checks that every expectation was met
+func (r *Recorder) AssertExpectations(t TestingT) bool {
+	t.Helper()
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	ok := true
+	for _, e := range r.expectations {
+		switch {
+		case e.times > 0 && e.calls != e.times:
+			t.Errorf("expected %s%v to be called %d times, got %d", e.method, e.args, e.times, e.calls)
+			ok = false
+		case e.times == 0 && e.calls == 0:
+			t.Errorf("expected %s%v to be called", e.method, e.args)
+			ok = false
+		}
+	}
+
+	if r.Strict {
+		for _, c := range r.unexpected {
+			t.Errorf("unexpected call to %s%v", c.Method, c.Args)
+			ok = false
+		}
+	}
+
+	return ok
+}
+
+// called records a call and returns the results of the first matching
+// expectation
+func (r *Recorder) called(method string, hasFunc bool, args ...interface{}) ([]interface{}, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	call := Call{Method: method, Args: args}
+	r.calls = append(r.calls, call)
+
+	for _, e := range r.expectations {
+		if e.matches(method, args) {
+			e.calls++
+			return e.results, true
+		}
+	}
+
+	if !hasFunc {
+		r.unexpected = append(r.unexpected, call)
+	}
+	return nil, false
+}
+
+// result returns the i-th result converted to T, or the zero value
+func result[T any](results []interface{}, i int) T {
+	var zero T
+	if i >= len(results) || results[i] == nil {
+		return zero
+	}
+	v, ok := results[i].(T)
+	if !ok {
+		panic(fmt.Sprintf("watchermock: result %d is %T, not %T", i, results[i], zero))
+	}
+	return v
+}
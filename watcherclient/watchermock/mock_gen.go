@@ -0,0 +1,335 @@
+// Code generated by mockgen. DO NOT EDIT.
+
+package watchermock
+
+import "github.com/overwatch144/golang-watcherclient/watcherclient"
+
+// Mock is a configurable implementation of watcherclient.API. A call
+// returns the results of the first matching expectation, then the result
+// of the method's Func field when set, and zero values otherwise.
+type Mock struct {
+	Recorder
+
+	CancelActionPlanFunc        func(string) (*watcherclient.ActionPlan, error)
+	CreateAuditFunc             func(*watcherclient.Audit) (*watcherclient.Audit, error)
+	CreateAuditTemplateFunc     func(*watcherclient.AuditTemplate) (*watcherclient.AuditTemplate, error)
+	DeleteActionPlanFunc        func(string) error
+	DeleteAuditFunc             func(string) error
+	DeleteAuditTemplateFunc     func(string) error
+	GetActionFunc               func(string) (*watcherclient.Action, error)
+	GetActionPlanFunc           func(string) (*watcherclient.ActionPlan, error)
+	GetAuditFunc                func(string) (*watcherclient.Audit, error)
+	GetAuditTemplateFunc        func(string) (*watcherclient.AuditTemplate, error)
+	GetComputeDataModelFunc     func() (*watcherclient.ComputeDataModel, error)
+	GetDataModelFunc            func(string) (*watcherclient.DataModel, error)
+	GetGoalFunc                 func(string) (*watcherclient.Goal, error)
+	GetServiceFunc              func(string) (*watcherclient.Service, error)
+	GetStorageDataModelFunc     func() (*watcherclient.StorageDataModel, error)
+	GetStrategyFunc             func(string) (*watcherclient.Strategy, error)
+	GetVersionFunc              func() (map[string]interface{}, error)
+	ListActionPlansFunc         func(*watcherclient.ListOptions) ([]watcherclient.ActionPlan, error)
+	ListActionsFunc             func(*watcherclient.ListOptions) ([]watcherclient.Action, error)
+	ListActionsByActionPlanFunc func(string) ([]watcherclient.Action, error)
+	ListAuditTemplatesFunc      func(*watcherclient.ListOptions) ([]watcherclient.AuditTemplate, error)
+	ListAuditsFunc              func(*watcherclient.ListOptions) ([]watcherclient.Audit, error)
+	ListGoalsFunc               func(*watcherclient.ListOptions) ([]watcherclient.Goal, error)
+	ListServicesFunc            func(*watcherclient.ListOptions) ([]watcherclient.Service, error)
+	ListStrategiesFunc          func(*watcherclient.ListOptions) ([]watcherclient.Strategy, error)
+	ListStrategiesByGoalFunc    func(string) ([]watcherclient.Strategy, error)
+	PingFunc                    func() error
+	StartActionPlanFunc         func(string) (*watcherclient.ActionPlan, error)
+	StartAuditFunc              func(string) (*watcherclient.Audit, error)
+	UpdateActionPlanFunc        func(string, map[string]interface{}) (*watcherclient.ActionPlan, error)
+	UpdateAuditFunc             func(string, map[string]interface{}) (*watcherclient.Audit, error)
+	UpdateAuditTemplateFunc     func(string, map[string]interface{}) (*watcherclient.AuditTemplate, error)
+}
+
+var _ watcherclient.API = (*Mock)(nil)
+
+// CancelActionPlan records the call and returns the configured results
+func (m *Mock) CancelActionPlan(a0 string) (*watcherclient.ActionPlan, error) {
+	results, ok := m.called("CancelActionPlan", m.CancelActionPlanFunc != nil, a0)
+	if !ok && m.CancelActionPlanFunc != nil {
+		return m.CancelActionPlanFunc(a0)
+	}
+	return result[*watcherclient.ActionPlan](results, 0), result[error](results, 1)
+}
+
+// CreateAudit records the call and returns the configured results
+func (m *Mock) CreateAudit(a0 *watcherclient.Audit) (*watcherclient.Audit, error) {
+	results, ok := m.called("CreateAudit", m.CreateAuditFunc != nil, a0)
+	if !ok && m.CreateAuditFunc != nil {
+		return m.CreateAuditFunc(a0)
+	}
+	return result[*watcherclient.Audit](results, 0), result[error](results, 1)
+}
+
+// CreateAuditTemplate records the call and returns the configured results
+func (m *Mock) CreateAuditTemplate(a0 *watcherclient.AuditTemplate) (*watcherclient.AuditTemplate, error) {
+	results, ok := m.called("CreateAuditTemplate", m.CreateAuditTemplateFunc != nil, a0)
+	if !ok && m.CreateAuditTemplateFunc != nil {
+		return m.CreateAuditTemplateFunc(a0)
+	}
+	return result[*watcherclient.AuditTemplate](results, 0), result[error](results, 1)
+}
+
+// DeleteActionPlan records the call and returns the configured results
+func (m *Mock) DeleteActionPlan(a0 string) error {
+	results, ok := m.called("DeleteActionPlan", m.DeleteActionPlanFunc != nil, a0)
+	if !ok && m.DeleteActionPlanFunc != nil {
+		return m.DeleteActionPlanFunc(a0)
+	}
+	return result[error](results, 0)
+}
+
+// DeleteAudit records the call and returns the configured results
+func (m *Mock) DeleteAudit(a0 string) error {
+	results, ok := m.called("DeleteAudit", m.DeleteAuditFunc != nil, a0)
+	if !ok && m.DeleteAuditFunc != nil {
+		return m.DeleteAuditFunc(a0)
+	}
+	return result[error](results, 0)
+}
+
+// DeleteAuditTemplate records the call and returns the configured results
+func (m *Mock) DeleteAuditTemplate(a0 string) error {
+	results, ok := m.called("DeleteAuditTemplate", m.DeleteAuditTemplateFunc != nil, a0)
+	if !ok && m.DeleteAuditTemplateFunc != nil {
+		return m.DeleteAuditTemplateFunc(a0)
+	}
+	return result[error](results, 0)
+}
+
+// GetAction records the call and returns the configured results
+func (m *Mock) GetAction(a0 string) (*watcherclient.Action, error) {
+	results, ok := m.called("GetAction", m.GetActionFunc != nil, a0)
+	if !ok && m.GetActionFunc != nil {
+		return m.GetActionFunc(a0)
+	}
+	return result[*watcherclient.Action](results, 0), result[error](results, 1)
+}
+
+// GetActionPlan records the call and returns the configured results
+func (m *Mock) GetActionPlan(a0 string) (*watcherclient.ActionPlan, error) {
+	results, ok := m.called("GetActionPlan", m.GetActionPlanFunc != nil, a0)
+	if !ok && m.GetActionPlanFunc != nil {
+		return m.GetActionPlanFunc(a0)
+	}
+	return result[*watcherclient.ActionPlan](results, 0), result[error](results, 1)
+}
+
+// GetAudit records the call and returns the configured results
+func (m *Mock) GetAudit(a0 string) (*watcherclient.Audit, error) {
+	results, ok := m.called("GetAudit", m.GetAuditFunc != nil, a0)
+	if !ok && m.GetAuditFunc != nil {
+		return m.GetAuditFunc(a0)
+	}
+	return result[*watcherclient.Audit](results, 0), result[error](results, 1)
+}
+
+// GetAuditTemplate records the call and returns the configured results
+func (m *Mock) GetAuditTemplate(a0 string) (*watcherclient.AuditTemplate, error) {
+	results, ok := m.called("GetAuditTemplate", m.GetAuditTemplateFunc != nil, a0)
+	if !ok && m.GetAuditTemplateFunc != nil {
+		return m.GetAuditTemplateFunc(a0)
+	}
+	return result[*watcherclient.AuditTemplate](results, 0), result[error](results, 1)
+}
+
+// GetComputeDataModel records the call and returns the configured results
+func (m *Mock) GetComputeDataModel() (*watcherclient.ComputeDataModel, error) {
+	results, ok := m.called("GetComputeDataModel", m.GetComputeDataModelFunc != nil)
+	if !ok && m.GetComputeDataModelFunc != nil {
+		return m.GetComputeDataModelFunc()
+	}
+	return result[*watcherclient.ComputeDataModel](results, 0), result[error](results, 1)
+}
+
+// GetDataModel records the call and returns the configured results
+func (m *Mock) GetDataModel(a0 string) (*watcherclient.DataModel, error) {
+	results, ok := m.called("GetDataModel", m.GetDataModelFunc != nil, a0)
+	if !ok && m.GetDataModelFunc != nil {
+		return m.GetDataModelFunc(a0)
+	}
+	return result[*watcherclient.DataModel](results, 0), result[error](results, 1)
+}
+
+// GetGoal records the call and returns the configured results
+func (m *Mock) GetGoal(a0 string) (*watcherclient.Goal, error) {
+	results, ok := m.called("GetGoal", m.GetGoalFunc != nil, a0)
+	if !ok && m.GetGoalFunc != nil {
+		return m.GetGoalFunc(a0)
+	}
+	return result[*watcherclient.Goal](results, 0), result[error](results, 1)
+}
+
+// GetService records the call and returns the configured results
+func (m *Mock) GetService(a0 string) (*watcherclient.Service, error) {
+	results, ok := m.called("GetService", m.GetServiceFunc != nil, a0)
+	if !ok && m.GetServiceFunc != nil {
+		return m.GetServiceFunc(a0)
+	}
+	return result[*watcherclient.Service](results, 0), result[error](results, 1)
+}
+
+// GetStorageDataModel records the call and returns the configured results
+func (m *Mock) GetStorageDataModel() (*watcherclient.StorageDataModel, error) {
+	results, ok := m.called("GetStorageDataModel", m.GetStorageDataModelFunc != nil)
+	if !ok && m.GetStorageDataModelFunc != nil {
+		return m.GetStorageDataModelFunc()
+	}
+	return result[*watcherclient.StorageDataModel](results, 0), result[error](results, 1)
+}
+
+// GetStrategy records the call and returns the configured results
+func (m *Mock) GetStrategy(a0 string) (*watcherclient.Strategy, error) {
+	results, ok := m.called("GetStrategy", m.GetStrategyFunc != nil, a0)
+	if !ok && m.GetStrategyFunc != nil {
+		return m.GetStrategyFunc(a0)
+	}
+	return result[*watcherclient.Strategy](results, 0), result[error](results, 1)
+}
+
+// GetVersion records the call and returns the configured results
+func (m *Mock) GetVersion() (map[string]interface{}, error) {
+	results, ok := m.called("GetVersion", m.GetVersionFunc != nil)
+	if !ok && m.GetVersionFunc != nil {
+		return m.GetVersionFunc()
+	}
+	return result[map[string]interface{}](results, 0), result[error](results, 1)
+}
+
+// ListActionPlans records the call and returns the configured results
+func (m *Mock) ListActionPlans(a0 *watcherclient.ListOptions) ([]watcherclient.ActionPlan, error) {
+	results, ok := m.called("ListActionPlans", m.ListActionPlansFunc != nil, a0)
+	if !ok && m.ListActionPlansFunc != nil {
+		return m.ListActionPlansFunc(a0)
+	}
+	return result[[]watcherclient.ActionPlan](results, 0), result[error](results, 1)
+}
+
+// ListActions records the call and returns the configured results
+func (m *Mock) ListActions(a0 *watcherclient.ListOptions) ([]watcherclient.Action, error) {
+	results, ok := m.called("ListActions", m.ListActionsFunc != nil, a0)
+	if !ok && m.ListActionsFunc != nil {
+		return m.ListActionsFunc(a0)
+	}
+	return result[[]watcherclient.Action](results, 0), result[error](results, 1)
+}
+
+// ListActionsByActionPlan records the call and returns the configured results
+func (m *Mock) ListActionsByActionPlan(a0 string) ([]watcherclient.Action, error) {
+	results, ok := m.called("ListActionsByActionPlan", m.ListActionsByActionPlanFunc != nil, a0)
+	if !ok && m.ListActionsByActionPlanFunc != nil {
+		return m.ListActionsByActionPlanFunc(a0)
+	}
+	return result[[]watcherclient.Action](results, 0), result[error](results, 1)
+}
+
+// ListAuditTemplates records the call and returns the configured results
+func (m *Mock) ListAuditTemplates(a0 *watcherclient.ListOptions) ([]watcherclient.AuditTemplate, error) {
+	results, ok := m.called("ListAuditTemplates", m.ListAuditTemplatesFunc != nil, a0)
+	if !ok && m.ListAuditTemplatesFunc != nil {
+		return m.ListAuditTemplatesFunc(a0)
+	}
+	return result[[]watcherclient.AuditTemplate](results, 0), result[error](results, 1)
+}
+
+// ListAudits records the call and returns the configured results
+func (m *Mock) ListAudits(a0 *watcherclient.ListOptions) ([]watcherclient.Audit, error) {
+	results, ok := m.called("ListAudits", m.ListAuditsFunc != nil, a0)
+	if !ok && m.ListAuditsFunc != nil {
+		return m.ListAuditsFunc(a0)
+	}
+	return result[[]watcherclient.Audit](results, 0), result[error](results, 1)
+}
+
+// ListGoals records the call and returns the configured results
+func (m *Mock) ListGoals(a0 *watcherclient.ListOptions) ([]watcherclient.Goal, error) {
+	results, ok := m.called("ListGoals", m.ListGoalsFunc != nil, a0)
+	if !ok && m.ListGoalsFunc != nil {
+		return m.ListGoalsFunc(a0)
+	}
+	return result[[]watcherclient.Goal](results, 0), result[error](results, 1)
+}
+
+// ListServices records the call and returns the configured results
+func (m *Mock) ListServices(a0 *watcherclient.ListOptions) ([]watcherclient.Service, error) {
+	results, ok := m.called("ListServices", m.ListServicesFunc != nil, a0)
+	if !ok && m.ListServicesFunc != nil {
+		return m.ListServicesFunc(a0)
+	}
+	return result[[]watcherclient.Service](results, 0), result[error](results, 1)
+}
+
+// ListStrategies records the call and returns the configured results
+func (m *Mock) ListStrategies(a0 *watcherclient.ListOptions) ([]watcherclient.Strategy, error) {
+	results, ok := m.called("ListStrategies", m.ListStrategiesFunc != nil, a0)
+	if !ok && m.ListStrategiesFunc != nil {
+		return m.ListStrategiesFunc(a0)
+	}
+	return result[[]watcherclient.Strategy](results, 0), result[error](results, 1)
+}
+
+// ListStrategiesByGoal records the call and returns the configured results
+func (m *Mock) ListStrategiesByGoal(a0 string) ([]watcherclient.Strategy, error) {
+	results, ok := m.called("ListStrategiesByGoal", m.ListStrategiesByGoalFunc != nil, a0)
+	if !ok && m.ListStrategiesByGoalFunc != nil {
+		return m.ListStrategiesByGoalFunc(a0)
+	}
+	return result[[]watcherclient.Strategy](results, 0), result[error](results, 1)
+}
+
+// Ping records the call and returns the configured results
+func (m *Mock) Ping() error {
+	results, ok := m.called("Ping", m.PingFunc != nil)
+	if !ok && m.PingFunc != nil {
+		return m.PingFunc()
+	}
+	return result[error](results, 0)
+}
+
+// StartActionPlan records the call and returns the configured results
+func (m *Mock) StartActionPlan(a0 string) (*watcherclient.ActionPlan, error) {
+	results, ok := m.called("StartActionPlan", m.StartActionPlanFunc != nil, a0)
+	if !ok && m.StartActionPlanFunc != nil {
+		return m.StartActionPlanFunc(a0)
+	}
+	return result[*watcherclient.ActionPlan](results, 0), result[error](results, 1)
+}
+
+// StartAudit records the call and returns the configured results
+func (m *Mock) StartAudit(a0 string) (*watcherclient.Audit, error) {
+	results, ok := m.called("StartAudit", m.StartAuditFunc != nil, a0)
+	if !ok && m.StartAuditFunc != nil {
+		return m.StartAuditFunc(a0)
+	}
+	return result[*watcherclient.Audit](results, 0), result[error](results, 1)
+}
+
+// UpdateActionPlan records the call and returns the configured results
+func (m *Mock) UpdateActionPlan(a0 string, a1 map[string]interface{}) (*watcherclient.ActionPlan, error) {
+	results, ok := m.called("UpdateActionPlan", m.UpdateActionPlanFunc != nil, a0, a1)
+	if !ok && m.UpdateActionPlanFunc != nil {
+		return m.UpdateActionPlanFunc(a0, a1)
+	}
+	return result[*watcherclient.ActionPlan](results, 0), result[error](results, 1)
+}
+
+// UpdateAudit records the call and returns the configured results
+func (m *Mock) UpdateAudit(a0 string, a1 map[string]interface{}) (*watcherclient.Audit, error) {
+	results, ok := m.called("UpdateAudit", m.UpdateAuditFunc != nil, a0, a1)
+	if !ok && m.UpdateAuditFunc != nil {
+		return m.UpdateAuditFunc(a0, a1)
+	}
+	return result[*watcherclient.Audit](results, 0), result[error](results, 1)
+}
+
+// UpdateAuditTemplate records the call and returns the configured results
+func (m *Mock) UpdateAuditTemplate(a0 string, a1 map[string]interface{}) (*watcherclient.AuditTemplate, error) {
+	results, ok := m.called("UpdateAuditTemplate", m.UpdateAuditTemplateFunc != nil, a0, a1)
+	if !ok && m.UpdateAuditTemplateFunc != nil {
+		return m.UpdateAuditTemplateFunc(a0, a1)
+	}
+	return result[*watcherclient.AuditTemplate](results, 0), result[error](results, 1)
+}
@@ -0,0 +1,77 @@
+package watchermock
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+)
+
+// recordingT captures assertion failures
+type recordingT struct {
+	errors []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+// Test 1: Expectations return configured values and are counted
+func TestExpectations(t *testing.T) {
+	m := &Mock{}
+	var api watcherclient.API = m
+
+	m.On("GetAudit", "uuid-1").Return(&watcherclient.Audit{UUID: "uuid-1"}, nil).Once()
+	m.On("DeleteAudit", Any).Return(errors.New("boom"))
+
+	audit, err := api.GetAudit("uuid-1")
+	if err != nil || audit == nil || audit.UUID != "uuid-1" {
+		t.Errorf("Expected audit uuid-1, got %+v, %v", audit, err)
+	}
+
+	// The expectation is exhausted, so the zero values are returned
+	audit, err = api.GetAudit("uuid-1")
+	if audit != nil || err != nil {
+		t.Errorf("Expected zero values, got %+v, %v", audit, err)
+	}
+
+	if err := api.DeleteAudit("anything"); err == nil || err.Error() != "boom" {
+		t.Errorf("Expected boom, got %v", err)
+	}
+
+	if len(m.CallsTo("GetAudit")) != 2 {
+		t.Errorf("Expected 2 GetAudit calls, got %d", len(m.CallsTo("GetAudit")))
+	}
+
+	if !m.AssertExpectations(t) {
+		t.Error("Expected expectations to be met")
+	}
+}
+
+// Test 2: Func fields and strict mode
+func TestFuncsAndStrict(t *testing.T) {
+	m := &Mock{Recorder: Recorder{Strict: true}}
+	m.ListGoalsFunc = func(opts *watcherclient.ListOptions) ([]watcherclient.Goal, error) {
+		return []watcherclient.Goal{{Name: "dummy"}}, nil
+	}
+	m.On("Ping").Times(2)
+
+	goals, err := m.ListGoals(nil)
+	if err != nil || len(goals) != 1 || goals[0].Name != "dummy" {
+		t.Errorf("Expected dummy goal, got %+v, %v", goals, err)
+	}
+
+	m.Ping()
+	m.GetGoal("unknown")
+
+	rt := &recordingT{}
+	if m.AssertExpectations(rt) {
+		t.Error("Expected AssertExpectations to fail")
+	}
+	if len(rt.errors) != 2 {
+		t.Errorf("Expected 2 failures, got %v", rt.errors)
+	}
+}
@@ -0,0 +1,51 @@
+package watchertest
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock provides the current time to the fake server
+type Clock interface {
+	Now() time.Time
+}
+
+// realClock uses the system time
+type realClock struct{}
+
+// Now returns the current system time
+func (realClock) Now() time.Time {
+	return time.Now().UTC()
+}
+
+// FakeClock is a manually advanced clock driving the state transitions
+type FakeClock struct {
+	now   time.Time
+	mutex sync.RWMutex
+}
+
+// NewFakeClock creates a fake clock set to the given time
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now.UTC()}
+}
+
+// Now returns the current fake time
+func (c *FakeClock) Now() time.Time {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.now
+}
+
+// Advance moves the clock forward
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Set sets the clock to the given time
+func (c *FakeClock) Set(now time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = now.UTC()
+}
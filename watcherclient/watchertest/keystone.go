@@ -0,0 +1,260 @@
+package watchertest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// tokenInfo describes an issued token
+type tokenInfo struct {
+	methods   []string
+	issuedAt  time.Time
+	expiresAt time.Time
+	project   string // Project name, empty for system-scoped tokens
+	system    bool
+}
+
+// issueToken creates a token valid for the configured TTL
+func (s *Server) issueToken(methods ...string) string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic("watchertest: failed to generate token: " + err.Error())
+	}
+	id := "gAAAA" + hex.EncodeToString(b)
+
+	now := s.opts.Clock.Now()
+	s.tokens[id] = &tokenInfo{
+		methods:   methods,
+		issuedAt:  now,
+		expiresAt: now.Add(s.opts.TokenTTL),
+		project:   s.opts.ProjectName,
+	}
+	return id
+}
+
+// validToken checks that the token was issued and has not expired
+func (s *Server) validToken(id string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	info, ok := s.tokens[id]
+	if !ok {
+		return false
+	}
+	return s.opts.Clock.Now().Before(info.expiresAt)
+}
+
+// serveIdentity implements the Keystone token endpoints
+func (s *Server) serveIdentity(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, IdentityPrefix), "/")
+
+	s.mutex.Lock()
+	delay := s.keystone
+	s.mutex.Unlock()
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+
+	if path != "/v3/auth/tokens" {
+		writeError(w, http.StatusNotFound, "identity path not found: "+path)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		s.createToken(w, r)
+	case http.MethodGet, http.MethodHead:
+		s.showToken(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "")
+	}
+}
+
+// authRequest is the subset of the Keystone v3 auth request checked here
+type authRequest struct {
+	Auth struct {
+		Identity struct {
+			Methods  []string `json:"methods"`
+			Password struct {
+				User struct {
+					ID       string `json:"id"`
+					Name     string `json:"name"`
+					Password string `json:"password"`
+				} `json:"user"`
+			} `json:"password"`
+			Token struct {
+				ID string `json:"id"`
+			} `json:"token"`
+			TOTP struct {
+				User struct {
+					Passcode string `json:"passcode"`
+				} `json:"user"`
+			} `json:"totp"`
+			ApplicationCredential struct {
+				ID     string `json:"id"`
+				Name   string `json:"name"`
+				Secret string `json:"secret"`
+			} `json:"application_credential"`
+		} `json:"identity"`
+		Scope struct {
+			Project *struct {
+				ID   string `json:"id"`
+				Name string `json:"name"`
+			} `json:"project"`
+			System map[string]bool `json:"system"`
+		} `json:"scope"`
+	} `json:"auth"`
+}
+
+// createToken authenticates a user and issues a token
+func (s *Server) createToken(w http.ResponseWriter, r *http.Request) {
+	var req authRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "malformed auth request: "+err.Error())
+		return
+	}
+
+	identity := req.Auth.Identity
+	if s.opts.Passcode != "" && contains(identity.Methods, "password") && !contains(identity.Methods, "totp") {
+		writeError(w, http.StatusUnauthorized, "Additional authentications steps required.")
+		return
+	}
+	for _, method := range identity.Methods {
+		switch method {
+		case "password":
+			user := identity.Password.User
+			if s.opts.Username != "" && (user.Name != s.opts.Username || user.Password != s.opts.Password) {
+				writeError(w, http.StatusUnauthorized, "The request you have made requires authentication.")
+				return
+			}
+		case "token":
+			if !s.validToken(identity.Token.ID) {
+				writeError(w, http.StatusNotFound, "Could not find token.")
+				return
+			}
+		case "totp":
+			if identity.TOTP.User.Passcode != s.opts.Passcode {
+				writeError(w, http.StatusUnauthorized, "The request you have made requires authentication.")
+				return
+			}
+		case "application_credential":
+			if identity.ApplicationCredential.Secret == "" {
+				writeError(w, http.StatusUnauthorized, "The request you have made requires authentication.")
+				return
+			}
+		}
+	}
+
+	s.mutex.Lock()
+	id := s.issueToken(identity.Methods...)
+	scope := req.Auth.Scope
+	switch {
+	case scope.System["all"]:
+		s.tokens[id].project = ""
+		s.tokens[id].system = true
+	case scope.Project != nil && scope.Project.Name != "":
+		s.tokens[id].project = scope.Project.Name
+	case scope.Project != nil && scope.Project.ID != "":
+		s.tokens[id].project = strings.TrimPrefix(scope.Project.ID, "project-")
+	}
+	body := s.tokenBody(id)
+	s.mutex.Unlock()
+
+	w.Header().Set("X-Subject-Token", id)
+	writeJSON(w, http.StatusCreated, body)
+}
+
+// showToken validates a token, as done by the client to learn its expiry
+func (s *Server) showToken(w http.ResponseWriter, r *http.Request) {
+	subject := r.Header.Get("X-Subject-Token")
+	if !s.validToken(r.Header.Get("X-Auth-Token")) || !s.validToken(subject) {
+		writeError(w, http.StatusNotFound, "Could not find token.")
+		return
+	}
+
+	s.mutex.Lock()
+	body := s.tokenBody(subject)
+	s.mutex.Unlock()
+
+	w.Header().Set("X-Subject-Token", subject)
+	writeJSON(w, http.StatusOK, body)
+}
+
+// tokenBody builds the Keystone token document
+func (s *Server) tokenBody(id string) map[string]interface{} {
+	info := s.tokens[id]
+
+	username := s.opts.Username
+	if username == "" {
+		username = "admin"
+	}
+
+	roles := make([]map[string]string, 0, len(s.opts.Roles))
+	for i, role := range s.opts.Roles {
+		roles = append(roles, map[string]string{"id": fmt.Sprintf("role-%d", i), "name": role})
+	}
+
+	endpoints := []map[string]string{}
+	for _, iface := range []string{"public", "internal", "admin"} {
+		endpoints = append(endpoints, map[string]string{
+			"id":        "watcher-" + iface,
+			"interface": iface,
+			"region":    s.opts.Region,
+			"region_id": s.opts.Region,
+			"url":       s.Endpoint(),
+		})
+	}
+
+	domain := map[string]string{"id": "default", "name": "Default"}
+
+	token := map[string]interface{}{
+		"methods":    info.methods,
+		"issued_at":  info.issuedAt.Format(time.RFC3339Nano),
+		"expires_at": info.expiresAt.Format(time.RFC3339Nano),
+		"user": map[string]interface{}{
+			"id":     "user-" + username,
+			"name":   username,
+			"domain": domain,
+		},
+		"roles": roles,
+		"catalog": []map[string]interface{}{
+			{
+				"id":        "watcher",
+				"type":      "infra-optim",
+				"name":      "watcher",
+				"endpoints": endpoints,
+			},
+			{
+				"id":   "keystone",
+				"type": "identity",
+				"name": "keystone",
+				"endpoints": []map[string]string{{
+					"id":        "keystone-public",
+					"interface": "public",
+					"region":    s.opts.Region,
+					"region_id": s.opts.Region,
+					"url":       s.AuthURL(),
+				}},
+			},
+		},
+	}
+	if s.opts.OmitTokenExpiry {
+		delete(token, "expires_at")
+	}
+	if info.system {
+		token["system"] = map[string]bool{"all": true}
+	} else {
+		token["project"] = map[string]interface{}{
+			"id":     "project-" + info.project,
+			"name":   info.project,
+			"domain": domain,
+		}
+	}
+
+	return map[string]interface{}{"token": token}
+}
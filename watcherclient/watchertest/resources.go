@@ -0,0 +1,690 @@
+package watchertest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+)
+
+// route dispatches a Watcher v1 request. The server mutex is held.
+func (s *Server) route(w http.ResponseWriter, r *http.Request, path string) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	collection := parts[0]
+	id := ""
+	if len(parts) > 1 {
+		id = parts[1]
+	}
+	sub := ""
+	if len(parts) > 2 {
+		sub = parts[2]
+	}
+
+	switch {
+	case collection == "audits":
+		s.serveAudits(w, r, id)
+	case collection == "audit_templates":
+		s.serveAuditTemplates(w, r, id)
+	case collection == "action_plans" && sub == "actions":
+		s.listActions(w, r, id)
+	case collection == "action_plans":
+		s.serveActionPlans(w, r, id)
+	case collection == "actions" && id == "":
+		s.listActions(w, r, r.URL.Query().Get("action_plan_uuid"))
+	case collection == "actions":
+		s.showAction(w, r, id)
+	case collection == "goals" && sub == "strategies":
+		s.listStrategies(w, r, id)
+	case collection == "goals":
+		s.serveGoals(w, r, id)
+	case collection == "strategies" && id == "":
+		s.listStrategies(w, r, r.URL.Query().Get("goal"))
+	case collection == "strategies":
+		s.showStrategy(w, r, id)
+	case collection == "services":
+		s.serveServices(w, r, id)
+	case collection == "data_model":
+		s.serveDataModel(w, r)
+	default:
+		writeError(w, http.StatusNotFound, "The resource could not be found.")
+	}
+}
+
+// serveAudits implements the audit endpoints
+func (s *Server) serveAudits(w http.ResponseWriter, r *http.Request, id string) {
+	if id == "" {
+		switch r.Method {
+		case http.MethodGet:
+			var audits []watcherclient.Audit
+			for _, uuid := range s.sorted(s.auditUUIDs()) {
+				audit := s.audits[uuid].audit
+				if goal := r.URL.Query().Get("goal"); goal != "" && audit.Goal != goal {
+					continue
+				}
+				audits = append(audits, audit)
+			}
+			page, err := paginate(audits, func(a watcherclient.Audit) string { return a.UUID }, r.URL.Query())
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			writeJSON(w, http.StatusOK, map[string]interface{}{"audits": nonNil(page)})
+		case http.MethodPost:
+			s.createAudit(w, r)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "")
+		}
+		return
+	}
+
+	record := s.findAudit(id)
+	if record == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Audit %s could not be found", id))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, record.audit)
+	case http.MethodPatch:
+		s.patchAudit(w, r, record)
+	case http.MethodDelete:
+		if record.audit.State == AuditOngoing {
+			writeError(w, http.StatusBadRequest, "Audit in ONGOING state cannot be deleted")
+			return
+		}
+		delete(s.audits, record.audit.UUID)
+		writeJSON(w, http.StatusNoContent, nil)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "")
+	}
+}
+
+// createAudit validates and stores a new audit
+func (s *Server) createAudit(w http.ResponseWriter, r *http.Request) {
+	var audit watcherclient.Audit
+	if err := json.NewDecoder(r.Body).Decode(&audit); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid input: "+err.Error())
+		return
+	}
+
+	goal, ok := s.findGoal(audit.Goal)
+	if !ok {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Goal %s could not be found", audit.Goal))
+		return
+	}
+	audit.Goal = goal.Name
+
+	if audit.Strategy != "" {
+		strategy, ok := s.findStrategy(audit.Strategy)
+		if !ok || strategy.GoalUUID != goal.UUID {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Strategy %s could not be found for goal %s", audit.Strategy, goal.Name))
+			return
+		}
+		audit.Strategy = strategy.Name
+	}
+
+	switch audit.AuditType {
+	case "ONESHOT":
+		if audit.Interval != "" {
+			writeError(w, http.StatusBadRequest, "Interval of audit must not be set when ONESHOT")
+			return
+		}
+	case "CONTINUOUS":
+		if audit.Interval == "" {
+			writeError(w, http.StatusBadRequest, "Interval of audit must be specified for CONTINUOUS")
+			return
+		}
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid audit type: %s", audit.AuditType))
+		return
+	}
+
+	audit.UUID = newUUID()
+	if audit.Name == "" {
+		audit.Name = goal.Name + "-" + s.opts.Clock.Now().Format("20060102-150405")
+	}
+	audit.State = AuditPending
+	audit.Hostname = "controller"
+	audit.CreatedAt = s.timestamp()
+	audit.Links = s.links("audits", audit.UUID)
+
+	s.track(audit.UUID)
+	s.audits[audit.UUID] = &auditRecord{audit: audit, startedAt: s.opts.Clock.Now()}
+
+	writeJSON(w, http.StatusCreated, audit)
+}
+
+// patchAudit applies a JSON patch to an audit, enforcing state transitions
+func (s *Server) patchAudit(w http.ResponseWriter, r *http.Request, record *auditRecord) {
+	updated := record.audit
+	if err := applyPatch(r, &updated); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if updated.State != record.audit.State {
+		allowed := map[string][]string{
+			AuditPending:   {AuditOngoing, AuditCancelled},
+			AuditOngoing:   {AuditCancelled, AuditSuspended},
+			AuditSuspended: {AuditOngoing, AuditCancelled},
+		}
+		if !contains(allowed[record.audit.State], updated.State) {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("State transition not allowed: (%s -> %s)", record.audit.State, updated.State))
+			return
+		}
+		if updated.State == AuditOngoing {
+			record.startedAt = s.opts.Clock.Now()
+		}
+	}
+
+	updated.UpdatedAt = s.timestamp()
+	record.audit = updated
+	writeJSON(w, http.StatusOK, record.audit)
+}
+
+// serveAuditTemplates implements the audit template endpoints
+func (s *Server) serveAuditTemplates(w http.ResponseWriter, r *http.Request, id string) {
+	if id == "" {
+		switch r.Method {
+		case http.MethodGet:
+			var templates []watcherclient.AuditTemplate
+			for _, uuid := range s.sorted(keys(s.auditTemplates)) {
+				templates = append(templates, *s.auditTemplates[uuid])
+			}
+			page, err := paginate(templates, func(t watcherclient.AuditTemplate) string { return t.UUID }, r.URL.Query())
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			writeJSON(w, http.StatusOK, map[string]interface{}{"audit_templates": nonNil(page)})
+		case http.MethodPost:
+			s.createAuditTemplate(w, r)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "")
+		}
+		return
+	}
+
+	var template *watcherclient.AuditTemplate
+	for _, t := range s.auditTemplates {
+		if t.UUID == id || t.Name == id {
+			template = t
+			break
+		}
+	}
+	if template == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("AuditTemplate %s could not be found", id))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, template)
+	case http.MethodPatch:
+		updated := *template
+		if err := applyPatch(r, &updated); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if _, ok := s.findGoal(updated.Goal); !ok {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Goal %s could not be found", updated.Goal))
+			return
+		}
+		updated.UpdatedAt = s.timestamp()
+		*template = updated
+		writeJSON(w, http.StatusOK, template)
+	case http.MethodDelete:
+		delete(s.auditTemplates, template.UUID)
+		writeJSON(w, http.StatusNoContent, nil)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "")
+	}
+}
+
+// createAuditTemplate validates and stores a new audit template
+func (s *Server) createAuditTemplate(w http.ResponseWriter, r *http.Request) {
+	var template watcherclient.AuditTemplate
+	if err := json.NewDecoder(r.Body).Decode(&template); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid input: "+err.Error())
+		return
+	}
+
+	if template.Name == "" {
+		writeError(w, http.StatusBadRequest, "Audit template name is required")
+		return
+	}
+
+	for _, t := range s.auditTemplates {
+		if t.Name == template.Name {
+			writeError(w, http.StatusConflict, fmt.Sprintf("An audit_template with name %s already exists", template.Name))
+			return
+		}
+	}
+
+	goal, ok := s.findGoal(template.Goal)
+	if !ok {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Goal %s could not be found", template.Goal))
+		return
+	}
+	template.Goal = goal.Name
+
+	if template.Strategy != "" {
+		strategy, ok := s.findStrategy(template.Strategy)
+		if !ok || strategy.GoalUUID != goal.UUID {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Strategy %s could not be found for goal %s", template.Strategy, goal.Name))
+			return
+		}
+		template.Strategy = strategy.Name
+	}
+
+	template.UUID = newUUID()
+	template.CreatedAt = s.timestamp()
+	template.Links = s.links("audit_templates", template.UUID)
+
+	s.track(template.UUID)
+	s.auditTemplates[template.UUID] = &template
+
+	writeJSON(w, http.StatusCreated, template)
+}
+
+// serveActionPlans implements the action plan endpoints
+func (s *Server) serveActionPlans(w http.ResponseWriter, r *http.Request, id string) {
+	if id == "" {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "")
+			return
+		}
+		var plans []watcherclient.ActionPlan
+		for _, uuid := range s.sorted(s.planUUIDs()) {
+			plan := s.actionPlans[uuid].plan
+			if audit := r.URL.Query().Get("audit_uuid"); audit != "" && plan.AuditUUID != audit {
+				continue
+			}
+			plans = append(plans, plan)
+		}
+		page, err := paginate(plans, func(p watcherclient.ActionPlan) string { return p.UUID }, r.URL.Query())
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"action_plans": nonNil(page)})
+		return
+	}
+
+	record, ok := s.actionPlans[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("ActionPlan %s could not be found", id))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, record.plan)
+	case http.MethodPatch:
+		updated := record.plan
+		if err := applyPatch(r, &updated); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		switch {
+		case updated.State == record.plan.State:
+		case updated.State == PlanTriggered && record.plan.State == PlanRecommended:
+			s.triggerActionPlan(record)
+			writeJSON(w, http.StatusOK, record.plan)
+			return
+		case updated.State == PlanCancelled && contains([]string{PlanRecommended, PlanPending, PlanTriggered, PlanOngoing}, record.plan.State):
+			s.setActionStates(id, "CANCELLED")
+		default:
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("State transition not allowed: (%s -> %s)", record.plan.State, updated.State))
+			return
+		}
+
+		updated.UpdatedAt = s.timestamp()
+		record.plan = updated
+		writeJSON(w, http.StatusOK, record.plan)
+	case http.MethodDelete:
+		if record.plan.State == PlanOngoing || record.plan.State == PlanTriggered {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Action plan in %s state cannot be deleted", record.plan.State))
+			return
+		}
+		for uuid, action := range s.actions {
+			if action.ActionPlanUUID == id {
+				delete(s.actions, uuid)
+			}
+		}
+		delete(s.actionPlans, id)
+		writeJSON(w, http.StatusNoContent, nil)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "")
+	}
+}
+
+// listActions lists the actions, optionally filtered by action plan
+func (s *Server) listActions(w http.ResponseWriter, r *http.Request, planUUID string) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	if planUUID != "" {
+		if _, ok := s.actionPlans[planUUID]; !ok {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("ActionPlan %s could not be found", planUUID))
+			return
+		}
+	}
+
+	var actions []watcherclient.Action
+	for _, uuid := range s.sorted(keys(s.actions)) {
+		action := s.actions[uuid]
+		if planUUID != "" && action.ActionPlanUUID != planUUID {
+			continue
+		}
+		actions = append(actions, *action)
+	}
+
+	page, err := paginate(actions, func(a watcherclient.Action) string { return a.UUID }, r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"actions": nonNil(page)})
+}
+
+// showAction returns a single action
+func (s *Server) showAction(w http.ResponseWriter, r *http.Request, id string) {
+	action, ok := s.actions[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Action %s could not be found", id))
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "")
+		return
+	}
+	writeJSON(w, http.StatusOK, action)
+}
+
+// serveGoals implements the goal endpoints
+func (s *Server) serveGoals(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	if id == "" {
+		page, err := paginate(s.goals, func(g watcherclient.Goal) string { return g.UUID }, r.URL.Query())
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"goals": nonNil(page)})
+		return
+	}
+
+	goal, ok := s.findGoal(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Goal %s could not be found", id))
+		return
+	}
+	writeJSON(w, http.StatusOK, goal)
+}
+
+// listStrategies lists the strategies, optionally filtered by goal
+func (s *Server) listStrategies(w http.ResponseWriter, r *http.Request, goalID string) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var goalUUID string
+	if goalID != "" {
+		goal, ok := s.findGoal(goalID)
+		if !ok {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("Goal %s could not be found", goalID))
+			return
+		}
+		goalUUID = goal.UUID
+	}
+
+	var strategies []watcherclient.Strategy
+	for _, strategy := range s.strategies {
+		if goalUUID == "" || strategy.GoalUUID == goalUUID {
+			strategies = append(strategies, strategy)
+		}
+	}
+
+	page, err := paginate(strategies, func(st watcherclient.Strategy) string { return st.UUID }, r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"strategies": nonNil(page)})
+}
+
+// showStrategy returns a single strategy
+func (s *Server) showStrategy(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	strategy, ok := s.findStrategy(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Strategy %s could not be found", id))
+		return
+	}
+	writeJSON(w, http.StatusOK, strategy)
+}
+
+// serveServices implements the service endpoints
+func (s *Server) serveServices(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	if id == "" {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"services": nonNil(s.services)})
+		return
+	}
+
+	for _, service := range s.services {
+		if strconv.Itoa(service.ID) == id || service.Name == id {
+			writeJSON(w, http.StatusOK, service)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, fmt.Sprintf("Service %s could not be found", id))
+}
+
+// serveDataModel returns the configured data model
+func (s *Server) serveDataModel(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	dataModelType := r.URL.Query().Get("data_model_type")
+	if dataModelType == "" {
+		dataModelType = r.URL.Query().Get("type")
+	}
+	if dataModelType == "" {
+		dataModelType = watcherclient.DataModelTypeCompute
+	}
+
+	dm, ok := s.dataModels[dataModelType]
+	if !ok {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Unsupported data model type: %s", dataModelType))
+		return
+	}
+	writeJSON(w, http.StatusOK, dm)
+}
+
+// findAudit looks up an audit by UUID or name
+func (s *Server) findAudit(id string) *auditRecord {
+	if record, ok := s.audits[id]; ok {
+		return record
+	}
+	for _, record := range s.audits {
+		if record.audit.Name == id {
+			return record
+		}
+	}
+	return nil
+}
+
+// findGoal looks up a goal by UUID or name
+func (s *Server) findGoal(id string) (watcherclient.Goal, bool) {
+	for _, goal := range s.goals {
+		if goal.UUID == id || goal.Name == id {
+			return goal, true
+		}
+	}
+	return watcherclient.Goal{}, false
+}
+
+// findStrategy looks up a strategy by UUID or name
+func (s *Server) findStrategy(id string) (watcherclient.Strategy, bool) {
+	for _, strategy := range s.strategies {
+		if strategy.UUID == id || strategy.Name == id {
+			return strategy, true
+		}
+	}
+	return watcherclient.Strategy{}, false
+}
+
+// auditUUIDs returns the UUIDs of every audit
+func (s *Server) auditUUIDs() []string {
+	return keys(s.audits)
+}
+
+// planUUIDs returns the UUIDs of every action plan
+func (s *Server) planUUIDs() []string {
+	return keys(s.actionPlans)
+}
+
+// sorted orders UUIDs by creation
+func (s *Server) sorted(uuids []string) []string {
+	sort.Slice(uuids, func(i, j int) bool {
+		return s.order[uuids[i]] < s.order[uuids[j]]
+	})
+	return uuids
+}
+
+// patchOp is a single RFC 6902 operation
+type patchOp struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+// applyPatch applies the JSON patch in the request body to v
+func applyPatch(r *http.Request, v interface{}) error {
+	var ops []patchOp
+	if err := json.NewDecoder(r.Body).Decode(&ops); err != nil {
+		return fmt.Errorf("Invalid patch: %v", err)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	doc := map[string]interface{}{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	for _, op := range ops {
+		field := strings.TrimPrefix(op.Path, "/")
+		switch field {
+		case "uuid", "created_at", "updated_at", "deleted_at", "links":
+			return fmt.Errorf("'%s' is an internal attribute and can not be updated", op.Path)
+		}
+
+		switch op.Op {
+		case "replace", "add":
+			doc[field] = op.Value
+		case "remove":
+			delete(doc, field)
+		default:
+			return fmt.Errorf("Invalid patch operation: %s", op.Op)
+		}
+	}
+
+	data, err = json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// paginate applies the marker, limit and sort_dir query parameters
+func paginate[T any](items []T, uuidOf func(T) string, query url.Values) ([]T, error) {
+	if query.Get("sort_dir") == "desc" {
+		reversed := make([]T, len(items))
+		for i, item := range items {
+			reversed[len(items)-1-i] = item
+		}
+		items = reversed
+	}
+
+	if marker := query.Get("marker"); marker != "" {
+		found := false
+		for i, item := range items {
+			if uuidOf(item) == marker {
+				items = items[i+1:]
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("marker %s could not be found", marker)
+		}
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid limit: %s", limit)
+		}
+		if n > 0 && n < len(items) {
+			items = items[:n]
+		}
+	}
+
+	return items, nil
+}
+
+// nonNil returns an empty slice instead of nil, so lists encode as []
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
+
+// keys returns the keys of a map
+func keys[T any](m map[string]T) []string {
+	result := make([]string, 0, len(m))
+	for k := range m {
+		result = append(result, k)
+	}
+	return result
+}
+
+// contains reports whether value is in list
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,433 @@
+// Package watchertest runs an in-process fake of the Watcher API and of the
+// Keystone endpoints the client needs, for tests that should not depend on a
+// live cloud.
+//
+// Example use:
+//
+//	clock := watchertest.NewFakeClock(time.Now())
+//	server := watchertest.NewServer(watchertest.Options{Clock: clock})
+//	defer server.Close()
+//
+//	client, err := watcherclient.NewClient(server.ClientOptions())
+//	audit, err := client.CreateAudit(&watcherclient.Audit{AuditType: "ONESHOT", Goal: "dummy"})
+//	clock.Advance(time.Minute)
+//	audit, err = client.GetAudit(audit.UUID) // SUCCEEDED, action plan created
+package watchertest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+)
+
+// Default fake server settings
+const (
+	DefaultRegion             = "RegionOne"
+	DefaultTokenTTL           = time.Hour
+	DefaultAuditDuration      = time.Minute
+	DefaultActionPlanDuration = time.Minute
+)
+
+// URL prefixes served by the fake server
+const (
+	IdentityPrefix = "/identity"
+	WatcherPrefix  = "/infra-optim"
+)
+
+// Options configures the fake server
+type Options struct {
+	Clock              Clock         // Defaults to the system clock
+	Region             string        // Region of the catalog endpoints
+	Username           string        // Accepted user; any user is accepted when empty
+	Password           string        // Accepted password
+	Passcode           string        // TOTP passcode required with the password, when set
+	ProjectName        string        // Project reported in tokens
+	Roles              []string      // Roles reported in tokens and enforced with the default policy, defaults to admin
+	TokenTTL           time.Duration // Lifetime of issued tokens
+	AuditDuration      time.Duration // Time a ONESHOT audit stays ONGOING
+	ActionPlanDuration time.Duration // Time a triggered action plan stays ONGOING
+	TLS                bool          // Serve HTTPS with a self-signed certificate
+	NoAuth             bool          // Accept requests without token, as auth_strategy = noauth
+	OmitTokenExpiry    bool          // Leave expires_at out of token responses
+
+	// PlanActions returns the actions of the action plan produced by an
+	// audit. A single nop action is produced when nil.
+	PlanActions func(audit watcherclient.Audit) []watcherclient.Action
+}
+
+// Request records a request received by the fake server
+type Request struct {
+	Method    string
+	Path      string
+	Query     string
+	ProjectID string // X-Project-Id header, sent by noauth clients
+	Time      time.Time
+}
+
+// Fault describes an error injected into matching requests
+type Fault struct {
+	Method     string // Matches any method when empty
+	Path       string // Path prefix below the Watcher endpoint, e.g. "/v1/audits"
+	StatusCode int
+	Body       string
+	Times      int // Number of requests to fail, 0 fails until cleared
+}
+
+// Server is a stateful fake of the Watcher API
+type Server struct {
+	URL string
+
+	opts   Options
+	server *httptest.Server
+	mutex  sync.Mutex
+
+	tokens         map[string]*tokenInfo
+	audits         map[string]*auditRecord
+	auditTemplates map[string]*watcherclient.AuditTemplate
+	actionPlans    map[string]*planRecord
+	actions        map[string]*watcherclient.Action
+	goals          []watcherclient.Goal
+	strategies     []watcherclient.Strategy
+	services       []watcherclient.Service
+	dataModels     map[string]*watcherclient.DataModel
+	order          map[string]int // Creation order used for listing
+
+	faults   []*Fault
+	latency  map[string]time.Duration
+	keystone time.Duration // Delay of Keystone requests
+	requests []Request
+	sequence int
+}
+
+// NewServer starts a fake Watcher server
+func NewServer(opts Options) *Server {
+	if opts.Clock == nil {
+		opts.Clock = realClock{}
+	}
+	if opts.Region == "" {
+		opts.Region = DefaultRegion
+	}
+	if opts.ProjectName == "" {
+		opts.ProjectName = "admin"
+	}
+	if len(opts.Roles) == 0 {
+		opts.Roles = []string{"admin"}
+	}
+	if opts.TokenTTL == 0 {
+		opts.TokenTTL = DefaultTokenTTL
+	}
+	if opts.AuditDuration == 0 {
+		opts.AuditDuration = DefaultAuditDuration
+	}
+	if opts.ActionPlanDuration == 0 {
+		opts.ActionPlanDuration = DefaultActionPlanDuration
+	}
+
+	s := &Server{
+		opts:           opts,
+		tokens:         map[string]*tokenInfo{},
+		audits:         map[string]*auditRecord{},
+		auditTemplates: map[string]*watcherclient.AuditTemplate{},
+		actionPlans:    map[string]*planRecord{},
+		actions:        map[string]*watcherclient.Action{},
+		dataModels:     map[string]*watcherclient.DataModel{},
+		order:          map[string]int{},
+		latency:        map[string]time.Duration{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(IdentityPrefix+"/", s.serveIdentity)
+	mux.HandleFunc(WatcherPrefix+"/", s.serveWatcher)
+	mux.HandleFunc(WatcherPrefix, s.serveWatcher)
+
+	if opts.TLS {
+		s.server = httptest.NewTLSServer(mux)
+	} else {
+		s.server = httptest.NewServer(mux)
+	}
+	s.URL = s.server.URL
+	s.seed()
+	return s
+}
+
+// Close shuts down the server
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// CACertPEM returns the PEM encoded certificate of a TLS server, to be
+// trusted through ClientOptions.CACert
+func (s *Server) CACertPEM() []byte {
+	if s.server.TLS == nil {
+		return nil
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.server.Certificate().Raw})
+}
+
+// AuthURL returns the Keystone v3 endpoint of the fake server
+func (s *Server) AuthURL() string {
+	return s.URL + IdentityPrefix + "/v3"
+}
+
+// Endpoint returns the Watcher endpoint, without API version
+func (s *Server) Endpoint() string {
+	return s.URL + WatcherPrefix
+}
+
+// ClientOptions returns options authenticating against the fake Keystone
+func (s *Server) ClientOptions() watcherclient.ClientOptions {
+	username := s.opts.Username
+	if username == "" {
+		username = "admin"
+	}
+	password := s.opts.Password
+	if password == "" {
+		password = "secret"
+	}
+
+	return watcherclient.ClientOptions{
+		AuthURL:         s.AuthURL(),
+		Username:        username,
+		Password:        password,
+		ProjectName:     s.opts.ProjectName,
+		ProjectDomainID: "default",
+		UserDomainID:    "default",
+		Region:          s.opts.Region,
+		AllowReauth:     true,
+	}
+}
+
+// Token issues a valid token without going through Keystone
+func (s *Server) Token() string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.issueToken("token")
+}
+
+// Client returns a client using a pre-issued token
+func (s *Server) Client() *watcherclient.Client {
+	return watcherclient.NewClientWithToken(s.Endpoint(), s.Token())
+}
+
+// RevokeTokens invalidates every issued token, so that the next Watcher
+// request returns 401
+func (s *Server) RevokeTokens() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.tokens = map[string]*tokenInfo{}
+}
+
+// InjectError makes matching Watcher requests fail
+func (s *Server) InjectError(f Fault) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	fault := f
+	s.faults = append(s.faults, &fault)
+}
+
+// ClearErrors removes every injected error
+func (s *Server) ClearErrors() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.faults = nil
+}
+
+// SetLatency delays Watcher requests below the given path prefix. An empty
+// prefix applies to every request; a zero duration removes the delay.
+func (s *Server) SetLatency(path string, d time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if d == 0 {
+		delete(s.latency, path)
+		return
+	}
+	s.latency[path] = d
+}
+
+// SetKeystoneLatency delays every Keystone request; a zero duration removes
+// the delay
+func (s *Server) SetKeystoneLatency(d time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.keystone = d
+}
+
+// Requests returns the Watcher requests received so far
+func (s *Server) Requests() []Request {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// serveWatcher authenticates, applies injected behavior and routes a request
+func (s *Server) serveWatcher(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, WatcherPrefix)
+	if path == "" {
+		path = "/"
+	}
+
+	s.mutex.Lock()
+	s.requests = append(s.requests, Request{
+		Method:    r.Method,
+		Path:      path,
+		Query:     r.URL.RawQuery,
+		ProjectID: r.Header.Get("X-Project-Id"),
+		Time:      s.opts.Clock.Now(),
+	})
+	delay := s.latencyFor(path)
+	fault := s.faultFor(r.Method, path)
+	s.mutex.Unlock()
+
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+
+	if fault != nil {
+		writeError(w, fault.StatusCode, fault.Body)
+		return
+	}
+
+	// Version documents are public, as in Watcher
+	if path == "/" || path == "/v1" || path == "/v1/" {
+		s.serveVersions(w, path)
+		return
+	}
+
+	if !s.opts.NoAuth && !s.validToken(r.Header.Get("X-Auth-Token")) {
+		writeError(w, http.StatusUnauthorized, "The request you have made requires authentication.")
+		return
+	}
+
+	if !s.opts.NoAuth && !s.allowed(r.Method, path) {
+		writeError(w, http.StatusForbidden, "Policy doesn't allow this operation to be performed.")
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.advance()
+	s.route(w, r, strings.TrimPrefix(path, "/v1"))
+}
+
+// allowed applies the default Watcher policy to the configured roles:
+// admins can do everything, readers and members can read resources other
+// than the data model
+func (s *Server) allowed(method, path string) bool {
+	admin, reader := false, false
+	for _, role := range s.opts.Roles {
+		switch strings.ToLower(role) {
+		case "admin", "administrator":
+			admin = true
+		case "reader", "member", "_member_":
+			reader = true
+		}
+	}
+
+	if admin {
+		return true
+	}
+	return reader && method == http.MethodGet && !strings.HasPrefix(path, "/v1/data_model")
+}
+
+// latencyFor returns the longest configured delay matching path
+func (s *Server) latencyFor(path string) time.Duration {
+	var delay time.Duration
+	for prefix, d := range s.latency {
+		if strings.HasPrefix(path, prefix) && d > delay {
+			delay = d
+		}
+	}
+	return delay
+}
+
+// faultFor returns the first injected fault matching the request
+func (s *Server) faultFor(method, path string) *Fault {
+	for i, f := range s.faults {
+		if f.Method != "" && f.Method != method {
+			continue
+		}
+		if !strings.HasPrefix(path, f.Path) {
+			continue
+		}
+
+		matched := *f
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+		return &matched
+	}
+	return nil
+}
+
+// serveVersions returns the API version documents
+func (s *Server) serveVersions(w http.ResponseWriter, path string) {
+	v1 := map[string]interface{}{
+		"id":          "v1",
+		"status":      "CURRENT",
+		"min_version": "1.0",
+		"max_version": "1.4",
+		"links": []watcherclient.Link{
+			{Href: s.Endpoint() + "/v1/", Rel: "self"},
+		},
+	}
+
+	if path == "/" {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"id":              "v1",
+			"versions":        []interface{}{v1},
+			"default_version": v1,
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, v1)
+}
+
+// newUUID returns a random UUID
+func newUUID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("watchertest: failed to generate UUID: %v", err))
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	h := hex.EncodeToString(b)
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Openstack-Request-Id", "req-"+newUUID())
+	w.WriteHeader(status)
+	if v != nil {
+		json.NewEncoder(w).Encode(v)
+	}
+}
+
+// writeError writes an error in the Watcher error format
+func writeError(w http.ResponseWriter, status int, message string) {
+	if message == "" {
+		message = http.StatusText(status)
+	}
+	faultString, _ := json.Marshal(map[string]interface{}{
+		"faultstring": message,
+		"faultcode":   "Client",
+		"debuginfo":   nil,
+	})
+	writeJSON(w, status, map[string]interface{}{
+		"error_message": string(faultString),
+	})
+}
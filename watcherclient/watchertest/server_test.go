@@ -0,0 +1,128 @@
+package watchertest
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+)
+
+// Test 1: Keystone authentication and audit lifecycle
+func TestAuditLifecycle(t *testing.T) {
+	clock := NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	server := NewServer(Options{Clock: clock})
+	defer server.Close()
+
+	client, err := watcherclient.NewClient(server.ClientOptions())
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	if client.GetEndpoint() != server.Endpoint()+"/v1" {
+		t.Errorf("Expected endpoint %s/v1, got %s", server.Endpoint(), client.GetEndpoint())
+	}
+
+	audit, err := client.CreateAudit(&watcherclient.Audit{AuditType: "ONESHOT", Goal: "dummy"})
+	if err != nil {
+		t.Fatalf("CreateAudit failed: %v", err)
+	}
+
+	if audit.State != AuditPending {
+		t.Errorf("Expected state %s, got %s", AuditPending, audit.State)
+	}
+
+	clock.Advance(time.Second)
+	audit, err = client.GetAudit(audit.UUID)
+	if err != nil {
+		t.Fatalf("GetAudit failed: %v", err)
+	}
+	if audit.State != AuditOngoing {
+		t.Errorf("Expected state %s, got %s", AuditOngoing, audit.State)
+	}
+
+	clock.Advance(DefaultAuditDuration)
+	audit, err = client.GetAudit(audit.UUID)
+	if err != nil {
+		t.Fatalf("GetAudit failed: %v", err)
+	}
+	if audit.State != AuditSucceeded {
+		t.Errorf("Expected state %s, got %s", AuditSucceeded, audit.State)
+	}
+
+	plans, err := client.ListActionPlans(nil)
+	if err != nil {
+		t.Fatalf("ListActionPlans failed: %v", err)
+	}
+	if len(plans) != 1 || plans[0].State != PlanRecommended {
+		t.Fatalf("Expected one RECOMMENDED action plan, got %+v", plans)
+	}
+
+	if _, err := client.StartActionPlan(plans[0].UUID); err != nil {
+		t.Fatalf("StartActionPlan failed: %v", err)
+	}
+
+	clock.Advance(time.Second)
+	clock.Advance(DefaultActionPlanDuration)
+	plan, err := client.GetActionPlan(plans[0].UUID)
+	if err != nil {
+		t.Fatalf("GetActionPlan failed: %v", err)
+	}
+	if plan.State != PlanSucceeded {
+		t.Errorf("Expected state %s, got %s", PlanSucceeded, plan.State)
+	}
+
+	actions, err := client.ListActionsByActionPlan(plan.UUID)
+	if err != nil {
+		t.Fatalf("ListActionsByActionPlan failed: %v", err)
+	}
+	if len(actions) != 1 || actions[0].State != "SUCCEEDED" {
+		t.Errorf("Expected one SUCCEEDED action, got %+v", actions)
+	}
+}
+
+// Test 2: Invalid transitions and unknown goals are rejected
+func TestValidation(t *testing.T) {
+	server := NewServer(Options{})
+	defer server.Close()
+	client := server.Client()
+
+	_, err := client.CreateAudit(&watcherclient.Audit{AuditType: "ONESHOT", Goal: "unknown"})
+	if apiErr, ok := err.(*watcherclient.APIError); !ok || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for unknown goal, got %v", err)
+	}
+
+	if _, err := client.GetAudit("missing"); !watcherclient.IsNotFound(err) {
+		t.Errorf("Expected 404, got %v", err)
+	}
+}
+
+// Test 3: Injected errors and re-authentication
+func TestFaultsAndReauth(t *testing.T) {
+	server := NewServer(Options{})
+	defer server.Close()
+
+	client, err := watcherclient.NewClient(server.ClientOptions())
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	server.InjectError(Fault{Method: http.MethodGet, Path: "/v1/goals", StatusCode: http.StatusServiceUnavailable, Times: 1})
+
+	if _, err := client.ListGoals(nil); err == nil {
+		t.Error("Expected injected error")
+	}
+
+	goals, err := client.ListGoals(nil)
+	if err != nil {
+		t.Fatalf("ListGoals failed after fault: %v", err)
+	}
+	if len(goals) == 0 {
+		t.Error("Expected seeded goals")
+	}
+
+	server.RevokeTokens()
+	if _, err := client.ListGoals(&watcherclient.ListOptions{Limit: 2}); err != nil {
+		t.Errorf("Expected re-authentication to recover, got %v", err)
+	}
+}
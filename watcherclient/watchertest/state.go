@@ -0,0 +1,288 @@
+package watchertest
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/overwatch144/golang-watcherclient/watcherclient"
+)
+
+// Audit states
+const (
+	AuditPending   = "PENDING"
+	AuditOngoing   = "ONGOING"
+	AuditSucceeded = "SUCCEEDED"
+	AuditFailed    = "FAILED"
+	AuditCancelled = "CANCELLED"
+	AuditSuspended = "SUSPENDED"
+)
+
+// Action plan and action states
+const (
+	PlanRecommended = "RECOMMENDED"
+	PlanPending     = "PENDING"
+	PlanTriggered   = "TRIGGERED"
+	PlanOngoing     = "ONGOING"
+	PlanSucceeded   = "SUCCEEDED"
+	PlanFailed      = "FAILED"
+	PlanCancelled   = "CANCELLED"
+	PlanSuperseded  = "SUPERSEDED"
+)
+
+// auditRecord tracks an audit and the time it started running
+type auditRecord struct {
+	audit     watcherclient.Audit
+	startedAt time.Time
+}
+
+// planRecord tracks an action plan and the time it was triggered
+type planRecord struct {
+	plan        watcherclient.ActionPlan
+	triggeredAt time.Time
+}
+
+// SetAuditState forces the state of an audit, bypassing transition rules
+func (s *Server) SetAuditState(uuid, state string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	record, ok := s.audits[uuid]
+	if !ok {
+		return fmt.Errorf("audit '%s' not found", uuid)
+	}
+	record.audit.State = state
+	record.audit.UpdatedAt = s.timestamp()
+	return nil
+}
+
+// SetActionPlanState forces the state of an action plan, bypassing
+// transition rules
+func (s *Server) SetActionPlanState(uuid, state string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	record, ok := s.actionPlans[uuid]
+	if !ok {
+		return fmt.Errorf("action plan '%s' not found", uuid)
+	}
+	record.plan.State = state
+	record.plan.UpdatedAt = s.timestamp()
+	return nil
+}
+
+// SetDataModel sets the data model returned for the given type
+func (s *Server) SetDataModel(dataModelType string, dm *watcherclient.DataModel) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.dataModels[dataModelType] = dm
+}
+
+// AddService registers a Watcher service
+func (s *Server) AddService(service watcherclient.Service) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if service.ID == 0 {
+		service.ID = len(s.services) + 1
+	}
+	s.services = append(s.services, service)
+}
+
+// advance applies the state transitions due at the current clock time
+func (s *Server) advance() {
+	now := s.opts.Clock.Now()
+
+	for _, uuid := range s.sorted(s.auditUUIDs()) {
+		record := s.audits[uuid]
+		audit := &record.audit
+
+		if audit.State == AuditPending && now.After(record.startedAt) {
+			audit.State = AuditOngoing
+			audit.UpdatedAt = s.timestamp()
+		}
+
+		// Continuous audits keep running until cancelled
+		if audit.State == AuditOngoing && audit.AuditType != "CONTINUOUS" &&
+			!now.Before(record.startedAt.Add(s.opts.AuditDuration)) {
+			audit.State = AuditSucceeded
+			audit.UpdatedAt = s.timestamp()
+			s.createActionPlan(*audit)
+		}
+	}
+
+	for _, uuid := range s.sorted(s.planUUIDs()) {
+		record := s.actionPlans[uuid]
+		plan := &record.plan
+
+		if plan.State == PlanTriggered && now.After(record.triggeredAt) {
+			plan.State = PlanOngoing
+			plan.UpdatedAt = s.timestamp()
+			s.setActionStates(uuid, "ONGOING")
+		}
+
+		if plan.State == PlanOngoing && !now.Before(record.triggeredAt.Add(s.opts.ActionPlanDuration)) {
+			plan.State = PlanSucceeded
+			plan.UpdatedAt = s.timestamp()
+			s.setActionStates(uuid, "SUCCEEDED")
+		}
+	}
+}
+
+// createActionPlan creates the action plan produced by a successful audit
+func (s *Server) createActionPlan(audit watcherclient.Audit) {
+	strategy := audit.Strategy
+	if strategy == "" {
+		for _, st := range s.strategies {
+			if goal, ok := s.findGoal(audit.Goal); ok && st.GoalUUID == goal.UUID {
+				strategy = st.Name
+				break
+			}
+		}
+	}
+
+	uuid := newUUID()
+	s.track(uuid)
+	s.actionPlans[uuid] = &planRecord{
+		plan: watcherclient.ActionPlan{
+			UUID:           uuid,
+			AuditUUID:      audit.UUID,
+			State:          PlanRecommended,
+			Strategy:       strategy,
+			GlobalEfficacy: []interface{}{},
+			CreatedAt:      s.timestamp(),
+			Links:          s.links("action_plans", uuid),
+		},
+	}
+
+	var actions []watcherclient.Action
+	if s.opts.PlanActions != nil {
+		actions = s.opts.PlanActions(audit)
+	} else {
+		actions = []watcherclient.Action{{
+			ActionType: "nop",
+			Parameters: map[string]interface{}{"message": "Welcome"},
+		}}
+	}
+
+	for _, action := range actions {
+		if action.UUID == "" {
+			action.UUID = newUUID()
+		}
+		action.ActionPlanUUID = uuid
+		action.State = "PENDING"
+		action.CreatedAt = s.timestamp()
+		action.Links = s.links("actions", action.UUID)
+		a := action
+		s.track(a.UUID)
+		s.actions[a.UUID] = &a
+	}
+
+	if audit.AutoTrigger {
+		s.triggerActionPlan(s.actionPlans[uuid])
+	}
+}
+
+// triggerActionPlan launches an action plan and supersedes the others
+func (s *Server) triggerActionPlan(record *planRecord) {
+	record.plan.State = PlanTriggered
+	record.plan.UpdatedAt = s.timestamp()
+	record.triggeredAt = s.opts.Clock.Now()
+
+	for _, other := range s.actionPlans {
+		if other != record && other.plan.State == PlanRecommended {
+			other.plan.State = PlanSuperseded
+			other.plan.UpdatedAt = s.timestamp()
+		}
+	}
+}
+
+// setActionStates updates the state of the unfinished actions of a plan
+func (s *Server) setActionStates(planUUID, state string) {
+	for _, action := range s.actions {
+		if action.ActionPlanUUID != planUUID {
+			continue
+		}
+		switch action.State {
+		case "SUCCEEDED", "FAILED", "CANCELLED":
+			continue
+		}
+		action.State = state
+		action.UpdatedAt = s.timestamp()
+	}
+}
+
+// track records the creation order of a resource
+func (s *Server) track(uuid string) {
+	s.sequence++
+	s.order[uuid] = s.sequence
+}
+
+// timestamp returns the current clock time in the Watcher format
+func (s *Server) timestamp() string {
+	return s.opts.Clock.Now().Format(time.RFC3339)
+}
+
+// links returns the self and bookmark links of a resource
+func (s *Server) links(collection, uuid string) []watcherclient.Link {
+	return []watcherclient.Link{
+		{Href: fmt.Sprintf("%s/v1/%s/%s", s.Endpoint(), collection, uuid), Rel: "self"},
+		{Href: fmt.Sprintf("%s/%s/%s", s.Endpoint(), collection, uuid), Rel: "bookmark"},
+	}
+}
+
+// seed creates the goals, strategies and services of a fresh deployment
+func (s *Server) seed() {
+	goals := []struct {
+		name, display string
+		strategies    []string
+	}{
+		{"dummy", "Dummy goal", []string{"dummy"}},
+		{"server_consolidation", "Server Consolidation", []string{"basic", "vm_workload_consolidation", "node_resource_consolidation"}},
+		{"workload_balancing", "Workload Balancing", []string{"workload_stabilization", "workload_balance", "storage_capacity_balance"}},
+		{"thermal_optimization", "Thermal Optimization", []string{"outlet_temperature"}},
+		{"airflow_optimization", "Airflow Optimization", []string{"uniform_airflow"}},
+		{"noisy_neighbor", "Noisy Neighbor", []string{"noisy_neighbor"}},
+		{"cluster_maintaining", "Cluster Maintaining", []string{"host_maintenance"}},
+		{"hardware_maintenance", "Hardware Maintenance", []string{"zone_migration"}},
+		{"saving_energy", "Saving Energy", []string{"saving_energy"}},
+	}
+
+	now := s.timestamp()
+	for _, g := range goals {
+		goal := watcherclient.Goal{
+			UUID:        newUUID(),
+			Name:        g.name,
+			DisplayName: g.display,
+			Efficacy:    []watcherclient.EfficacyIndicatorSpec{},
+			CreatedAt:   now,
+		}
+		goal.Links = s.links("goals", goal.UUID)
+		s.goals = append(s.goals, goal)
+
+		for _, name := range g.strategies {
+			strategy := watcherclient.Strategy{
+				UUID:        newUUID(),
+				Name:        name,
+				DisplayName: name,
+				GoalUUID:    goal.UUID,
+				Parameters:  map[string]interface{}{"properties": map[string]interface{}{}},
+				CreatedAt:   now,
+			}
+			strategy.Links = s.links("strategies", strategy.UUID)
+			s.strategies = append(s.strategies, strategy)
+		}
+	}
+
+	for i, name := range []string{"watcher-api", "watcher-decision-engine", "watcher-applier"} {
+		s.services = append(s.services, watcherclient.Service{
+			ID:         i + 1,
+			Name:       name,
+			Host:       "controller",
+			Status:     "ACTIVE",
+			LastSeenUp: now,
+		})
+	}
+
+	s.dataModels[watcherclient.DataModelTypeCompute] = &watcherclient.DataModel{
+		Context: []map[string]interface{}{},
+	}
+}